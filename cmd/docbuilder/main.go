@@ -29,12 +29,22 @@ func main() {
 	}
 
 	cfg := builder.Config{}
+	var configPath string
 	fs := flag.NewFlagSet("doc-builder", flag.ExitOnError)
+	fs.StringVar(&configPath, "config", "", "Path to a docbuilder.yaml or docbuilder.json project config (default: discovered in --doc-dir)")
 	fs.StringVar(&cfg.Prefix, "prefix", "DOC_", "File name prefix to detect documentation sources")
-	fs.StringVar(&cfg.Engine, "engine", "vitepress", "Documentation engine to use (currently only 'vitepress')")
+	fs.StringVar(&cfg.Engine, "engine", "vitepress", fmt.Sprintf("Documentation engine to use (%s)", strings.Join(builder.EngineNames(), ", ")))
 	fs.StringVar(&cfg.SearchPath, "search", "", "Root path where prefixed markdown files will be discovered")
-	fs.StringVar(&cfg.DocDir, "doc-dir", ".", "Documentation workspace directory that contains .vitepress setup")
+	fs.StringVar(&cfg.DocDir, "doc-dir", ".", "Documentation workspace directory that contains the engine setup (for example .vitepress)")
 	fs.StringVar(&cfg.TempDirName, "temp-dir", "temp", "Name of the temporary build directory inside the documentation workspace")
+	fs.StringVar(&cfg.CacheDir, "cache-dir", "", "Directory for the node_modules cache (default: doc-builder inside the user cache directory)")
+	fs.StringVar(&cfg.PackageManager, "package-manager", "", "Node.js package manager: npm, pnpm, yarn or bun (default: detected from package.json and lockfile)")
+	fs.StringVar(&cfg.CategoryInference.Strategy, "category-strategy", "frontmatter", "How prefixed files without a front matter category are categorized: frontmatter, directory or mapping")
+	fs.StringVar(&cfg.OnCollision, "on-collision", "warn", "What to do when two sources map to the same page: error, warn, prefixed or existing")
+	fs.StringVar(&cfg.Audience, "audience", "", "Only publish pages and conditional blocks for this audience, for example internal or public (default: everything)")
+	fs.BoolVar(&cfg.APIReference.Enabled, "api-reference", false, "Generate a reference page for every Go package below the search path")
+	fs.StringVar(&cfg.APIReference.Category, "api-category", "api", "Category of the generated Go API reference pages")
+	fs.BoolVar(&cfg.IncludeDrafts, "include-drafts", false, "Publish pages marked draft: true in their front matter")
 	fs.BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose logging output")
 
 	fs.Usage = func() {
@@ -50,8 +60,13 @@ func main() {
 		os.Exit(2)
 	}
 
+	if err := applyConfigFile(fs, &cfg, configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(2)
+	}
+
 	if cfg.SearchPath == "" {
-		fmt.Fprintln(os.Stderr, "missing required flag: --search")
+		fmt.Fprintln(os.Stderr, "missing required flag: --search (or 'search' in the config file)")
 		fs.Usage()
 		os.Exit(2)
 	}
@@ -62,6 +77,43 @@ func main() {
 	}
 }
 
+// applyConfigFile loads the project config into cfg and then re-applies every
+// flag that was set explicitly, so the command line always wins.
+func applyConfigFile(fs *flag.FlagSet, cfg *builder.Config, configPath string) error {
+	if configPath == "" {
+		found, err := builder.FindConfigFile(cfg.DocDir)
+		if err != nil {
+			return err
+		}
+		configPath = found
+	}
+	if configPath == "" {
+		return nil
+	}
+
+	explicit := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+
+	loaded, err := builder.LoadConfigFile(configPath, *cfg)
+	if err != nil {
+		return err
+	}
+	*cfg = loaded
+
+	for name, value := range explicit {
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("failed to apply flag --%s: %w", name, err)
+		}
+	}
+
+	if cfg.Verbose {
+		fmt.Printf("Using config file %s\n", configPath)
+	}
+	return nil
+}
+
 func runHelper() {
 	message := `doc-builder helper
 
@@ -73,15 +125,22 @@ ready-to-ship VitePress site. The workflow is:
      directory, derive slugs, titles, and categories from front matter.
   3. Merge any existing markdown that already lives in the documentation folder
      (for example curated guides) so that hand-crafted content is preserved.
-  4. Compose the sidebar by translating the collected metadata into the
-     // SIDEBAR_ITEMS placeholder in .vitepress/base.config.js.
-  5. Install Node.js dependencies inside the temp directory when needed and run
-     \'npm run docs:build\' using the selected engine (currently only VitePress).
-  6. Copy the generated .vitepress/dist output back to the main docs workspace.
+  4. Let the selected engine compose the navigation. For VitePress the collected
+     metadata replaces the // SIDEBAR_ITEMS placeholder in
+     .vitepress/base.config.js.
+  5. Build the site with the engine tooling. VitePress installs Node.js
+     dependencies inside the temp directory when needed and runs
+     \'npm run docs:build\'.
+  6. Copy the generated output (.vitepress/dist for VitePress) back to the main
+     docs workspace.
 
 Typical usage:
   ./bin/doc-builder --search ../ --doc-dir . --prefix DOC_ --engine vitepress
 
+Every flag can also be stored in a docbuilder.yaml (or docbuilder.json) file
+inside the docs directory, or passed explicitly with --config. Flags given on
+the command line override values from the file.
+
 The process stops with a descriptive error if expected files like
 .vitepress/base.config.js or package.json cannot be found.
 `
@@ -2,3 +2,8 @@ module github.com/tkowalski29/doc-builder
 
 go 1.22
 
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/yuin/goldmark v1.8.6
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,375 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/doc/comment"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const originGenerated = "generated"
+
+// relativeLinkScheme lets go/doc/comment accept relative link definitions.
+const relativeLinkScheme = "file:///.relative/"
+
+const defaultAPICategory = "api"
+
+var (
+	goModulePattern      = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+	exampleOutputPattern = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+	linkDefPattern       = regexp.MustCompile(`(?m)^(\[[^\]\n]+\]:[ \t]+)(\S+)$`)
+)
+
+// APIReferenceConfig controls the generated Go API reference pages.
+type APIReferenceConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Category places the generated pages, "api" when empty.
+	Category string `yaml:"category" json:"category"`
+	// Exclude skips package directories matching these patterns.
+	Exclude []string `yaml:"exclude" json:"exclude"`
+}
+
+func (c APIReferenceConfig) validate() error {
+	for _, pattern := range c.Exclude {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Builder) generateAPIReference(env environment) ([]page, error) {
+	cfg := b.cfg.APIReference
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	category := normalizeCategoryPath(cfg.Category)
+	if category == "" {
+		category = defaultAPICategory
+	}
+	if b.cfg.Verbose {
+		fmt.Printf("  Generating Go API reference from %s\n", env.searchRoot)
+	}
+
+	var pages []page
+	err := filepath.WalkDir(env.searchRoot, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(env.searchRoot, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			name := d.Name()
+			if shouldSkipDirectory(name) || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if samePath(path, filepath.Join(env.docDir, b.cfg.TempDirName)) {
+				return filepath.SkipDir
+			}
+			for _, pattern := range cfg.Exclude {
+				if matchGlob(pattern, rel) {
+					return filepath.SkipDir
+				}
+			}
+		}
+
+		pkg, fset, err := loadGoPackage(path, goImportPath(path, env.searchRoot))
+		if err != nil || pkg == nil {
+			return err
+		}
+
+		slug, title := strings.ReplaceAll(rel, "/", "-"), rel
+		if rel == "." {
+			slug, title = pkg.Name, pkg.Name
+		}
+		source := apiSource(pkg)
+		data := []byte(renderAPIReference(fset, pkg, title))
+		fm, err := parseFrontMatter(source, data)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, page{
+			record: menuRecord{
+				CategoryPath: category,
+				Slug:         slug,
+				Title:        title,
+				Source:       source,
+				Origin:       originGenerated,
+				Hash:         contentHash(data),
+				FrontMatter:  fm,
+			},
+			rel:  category + "/" + slug + ".md",
+			data: data,
+		})
+		if b.cfg.Verbose {
+			fmt.Printf("  generated API reference for %s\n", pkg.ImportPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+func loadGoPackage(dir, importPath string) (*doc.Package, *token.FileSet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	name := ""
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || filepath.Ext(fileName) != ".go" {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, fileName); err != nil || !ok {
+			continue
+		}
+		path := filepath.Join(dir, fileName)
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if !strings.HasSuffix(fileName, "_test.go") && name == "" {
+			name = file.Name.Name
+		}
+		files = append(files, file)
+	}
+	if name == "" || name == "main" {
+		return nil, nil, nil
+	}
+
+	kept := files[:0]
+	for _, file := range files {
+		if file.Name.Name == name || file.Name.Name == name+"_test" {
+			kept = append(kept, file)
+		}
+	}
+
+	pkg, err := doc.NewFromFiles(fset, kept, importPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read documentation of %s: %w", dir, err)
+	}
+	if pkg.Doc == "" && len(pkg.Consts) == 0 && len(pkg.Vars) == 0 && len(pkg.Funcs) == 0 && len(pkg.Types) == 0 {
+		return nil, nil, nil
+	}
+	return pkg, fset, nil
+}
+
+// apiSource prefers doc.go, falling back to the first file of the package.
+func apiSource(pkg *doc.Package) string {
+	source := ""
+	for _, name := range pkg.Filenames {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if filepath.Base(name) == "doc.go" {
+			return name
+		}
+		if source == "" {
+			source = name
+		}
+	}
+	return source
+}
+
+func goImportPath(dir, searchRoot string) string {
+	for current := dir; ; current = filepath.Dir(current) {
+		//nolint:gosec // file path is validated and safe
+		if data, err := os.ReadFile(filepath.Join(current, "go.mod")); err == nil {
+			if match := goModulePattern.FindSubmatch(data); match != nil {
+				rel, err := filepath.Rel(current, dir)
+				if err == nil && rel != "." {
+					return string(match[1]) + "/" + filepath.ToSlash(rel)
+				}
+				return string(match[1])
+			}
+		}
+		if parent := filepath.Dir(current); parent == current {
+			break
+		}
+	}
+	if rel, err := filepath.Rel(searchRoot, dir); err == nil && rel != "." {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.Base(dir)
+}
+
+type apiWriter struct {
+	out  strings.Builder
+	fset *token.FileSet
+	pkg  *doc.Package
+}
+
+func renderAPIReference(fset *token.FileSet, pkg *doc.Package, title string) string {
+	w := &apiWriter{fset: fset, pkg: pkg}
+	fmt.Fprintf(&w.out, "---\ntitle: %s\n---\n\n", strconv.Quote(title))
+	fmt.Fprintf(&w.out, "# Package %s\n\n", pkg.Name)
+	w.code(fmt.Sprintf("import %q", pkg.ImportPath))
+	w.doc(pkg.Doc)
+	w.examples(pkg.Examples)
+
+	w.values("Constants", pkg.Consts)
+	w.values("Variables", pkg.Vars)
+
+	if len(pkg.Funcs) > 0 {
+		w.out.WriteString("## Functions\n\n")
+		for _, fn := range pkg.Funcs {
+			w.function(fn, "###")
+		}
+	}
+
+	if len(pkg.Types) > 0 {
+		w.out.WriteString("## Types\n\n")
+		for _, typ := range pkg.Types {
+			w.heading("###", typ.Name, "type "+typ.Name)
+			w.decl(typ.Decl)
+			w.doc(typ.Doc)
+			w.examples(typ.Examples)
+			for _, value := range append(append([]*doc.Value(nil), typ.Consts...), typ.Vars...) {
+				w.decl(value.Decl)
+				w.doc(value.Doc)
+			}
+			for _, fn := range typ.Funcs {
+				w.function(fn, "####")
+			}
+			for _, method := range typ.Methods {
+				w.function(method, "####")
+			}
+		}
+	}
+	return strings.TrimRight(w.out.String(), "\n") + "\n"
+}
+
+func (w *apiWriter) values(title string, values []*doc.Value) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(&w.out, "## %s\n\n", title)
+	for _, value := range values {
+		w.decl(value.Decl)
+		w.doc(value.Doc)
+	}
+}
+
+func (w *apiWriter) function(fn *doc.Func, level string) {
+	id, title := fn.Name, "func "+fn.Name
+	if fn.Recv != "" {
+		recv := strings.TrimPrefix(fn.Recv, "*")
+		if idx := strings.Index(recv, "["); idx >= 0 {
+			recv = recv[:idx]
+		}
+		id, title = recv+"."+fn.Name, fmt.Sprintf("func (%s) %s", strings.ReplaceAll(fn.Recv, "*", `\*`), fn.Name)
+	}
+	w.heading(level, id, title)
+	w.decl(fn.Decl)
+	w.doc(fn.Doc)
+	w.examples(fn.Examples)
+}
+
+// heading anchors the heading with the identifier doc links point at.
+func (w *apiWriter) heading(level, id, title string) {
+	fmt.Fprintf(&w.out, "<a id=\"%s\"></a>\n\n%s %s\n\n", id, level, title)
+}
+
+// decl prints the declaration without its doc comment.
+func (w *apiWriter) decl(decl ast.Decl) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		stripped := *d
+		stripped.Doc = nil
+		decl = &stripped
+	case *ast.GenDecl:
+		stripped := *d
+		stripped.Doc = nil
+		decl = &stripped
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, w.fset, decl); err != nil {
+		return
+	}
+	w.code(buf.String())
+}
+
+func (w *apiWriter) doc(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	p := w.pkg.Printer()
+	p.HeadingLevel = 3
+	p.HeadingID = func(*comment.Heading) string { return "" }
+	p.DocLinkBaseURL = "https://pkg.go.dev"
+	text = linkDefPattern.ReplaceAllStringFunc(text, func(def string) string {
+		match := linkDefPattern.FindStringSubmatch(def)
+		if strings.Contains(match[2], ":") {
+			return def
+		}
+		return match[1] + relativeLinkScheme + match[2]
+	})
+	markdown := string(p.Markdown(w.pkg.Parser().Parse(text)))
+	w.out.WriteString(strings.ReplaceAll(markdown, "]("+relativeLinkScheme, "]("))
+	w.out.WriteString("\n")
+}
+
+func (w *apiWriter) examples(examples []*doc.Example) {
+	sort.SliceStable(examples, func(i, j int) bool { return examples[i].Suffix < examples[j].Suffix })
+	for _, ex := range examples {
+		title := "Example"
+		if ex.Suffix != "" {
+			title += " (" + strings.ReplaceAll(ex.Suffix, "_", " ") + ")"
+		}
+		fmt.Fprintf(&w.out, "**%s**\n\n", title)
+		w.doc(ex.Doc)
+
+		var comments []*ast.CommentGroup
+		for _, group := range ex.Comments {
+			if !exampleOutputPattern.MatchString(group.Text()) {
+				comments = append(comments, group)
+			}
+		}
+		var buf bytes.Buffer
+		node := &printer.CommentedNode{Node: ex.Code, Comments: comments}
+		if err := format.Node(&buf, w.fset, node); err != nil {
+			continue
+		}
+		code := buf.String()
+		if _, ok := ex.Code.(*ast.BlockStmt); ok {
+			code = strings.TrimSuffix(strings.TrimPrefix(code, "{"), "}")
+		}
+		w.code(dedent(code))
+		if ex.Output != "" || ex.EmptyOutput {
+			w.out.WriteString("Output:\n\n")
+			w.out.WriteString(fencedBlock("text", strings.TrimRight(ex.Output, "\n")))
+			w.out.WriteString("\n")
+		}
+	}
+}
+
+func (w *apiWriter) code(code string) {
+	w.out.WriteString(fencedBlock("go", strings.TrimRight(code, "\n")))
+	w.out.WriteString("\n")
+}
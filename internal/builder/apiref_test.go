@@ -0,0 +1,108 @@
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIReference(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "go.mod"), "module example.com/shop\n\ngo 1.22\n")
+	writeTestFile(t, filepath.Join(root, "cart", "cart.go"), `// Package cart keeps the items of a basket.
+package cart
+
+// MaxItems limits the size of a [Cart].
+const MaxItems = 50
+
+// Cart holds items.
+type Cart struct {
+	Items []string
+	owner string
+}
+
+// New returns an empty cart.
+func New() *Cart { return &Cart{} }
+
+// Add appends an item.
+func (c *Cart) Add(item string) { c.Items = append(c.Items, item) }
+
+func helper() {}
+`)
+	writeTestFile(t, filepath.Join(root, "cart", "example_test.go"), `package cart_test
+
+import (
+	"fmt"
+
+	"example.com/shop/cart"
+)
+
+func ExampleCart_Add() {
+	c := cart.New()
+	c.Add("apple")
+	fmt.Println(len(c.Items))
+	// Output: 1
+}
+`)
+	writeTestFile(t, filepath.Join(root, "cmd", "shop", "main.go"), "package main\n\nfunc main() {}\n")
+	writeTestFile(t, filepath.Join(root, "cart", "testdata", "fixture.go"), "package fixture\n\nfunc Fixture() {}\n")
+	writeTestFile(t, filepath.Join(root, "internal", "store", "store.go"), "package store\n\nfunc Open() {}\n")
+
+	cfg := Config{APIReference: APIReferenceConfig{Enabled: true, Category: "reference/go", Exclude: []string{"internal/**"}}}
+	b := &Builder{cfg: cfg}
+	pages, err := b.generateAPIReference(environment{searchRoot: root, docDir: filepath.Join(root, "docs")})
+	if err != nil {
+		t.Fatalf("generateAPIReference returned error: %v", err)
+	}
+	if len(pages) != 1 {
+		t.Fatalf("expected only the cart package, got %d pages", len(pages))
+	}
+
+	p := pages[0]
+	if p.rel != "reference/go/cart.md" || p.record.Title != "cart" || p.record.Origin != originGenerated || p.record.Source != filepath.Join(root, "cart", "cart.go") {
+		t.Fatalf("unexpected page %+v", p.record)
+	}
+	content := string(p.data)
+	for _, expect := range []string{
+		"title: \"cart\"",
+		"# Package cart",
+		"import \"example.com/shop/cart\"",
+		"Package cart keeps the items of a basket.",
+		"## Constants\n\n```go\nconst MaxItems = 50\n```\n\nMaxItems limits the size of a [Cart](#Cart).",
+		"<a id=\"Cart\"></a>\n\n### type Cart",
+		"Items []string",
+		"#### func New",
+		"<a id=\"Cart.Add\"></a>\n\n#### func (\\*Cart) Add",
+		"func (c *Cart) Add(item string)\n```",
+		"**Example**\n\n```go\nc := cart.New()\nc.Add(\"apple\")\nfmt.Println(len(c.Items))\n```\n\nOutput:\n\n```text\n1\n```",
+	} {
+		if !strings.Contains(content, expect) {
+			t.Fatalf("expected %q in reference page:\n%s", expect, content)
+		}
+	}
+	for _, unexpected := range []string{"owner", "helper", "return &Cart"} {
+		if strings.Contains(content, unexpected) {
+			t.Fatalf("unexpected %q in reference page:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestGoImportPathWithoutModule(t *testing.T) {
+	root := t.TempDir()
+	if got := goImportPath(filepath.Join(root, "pkg", "util"), root); got != "pkg/util" {
+		t.Fatalf("unexpected import path %q", got)
+	}
+}
+
+func TestRunResolvesLinksInAPIReference(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Guide.md", "# Guide\n")
+	f.source("cart/doc.go", "// Package cart is described in the [guide].\n//\n// [guide]: ../DOC_Guide.md\npackage cart\n")
+	f.source("cart/cart.go", "package cart\n\n// New returns a cart.\nfunc New() {}\n")
+	f.cfg.APIReference = APIReferenceConfig{Enabled: true}
+	f.run()
+
+	if data := f.output("api/cart.html"); !strings.Contains(data, `<a href="../guides/guide.html">guide</a>`) {
+		t.Fatalf("expected doc comment link to resolve against the package directory:\n%s", data)
+	}
+}
@@ -0,0 +1,137 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// assetsDirName holds assets that cannot be placed next to the page that
+// references them.
+const assetsDirName = "assets"
+
+// asset is a local file referenced by a collected page.
+type asset struct {
+	source string
+	// rel is the slash separated target path relative to the content directory.
+	rel string
+}
+
+// missingAsset is a local reference to a file that does not exist.
+type missingAsset struct {
+	Source string
+	Line   int
+	Link   string
+}
+
+func (m missingAsset) String() string {
+	return fmt.Sprintf("%s:%d: %s", displayPath(m.Source), m.Line, m.Link)
+}
+
+// collectAssets finds the local files referenced by pages and rewrites the
+// references to the copy in the workspace. It runs before rewriteLinks, so
+// engine specific page links such as Hugo's relref shortcodes are never
+// mistaken for files. Files inside the directory of the
+// page source keep their relative location next to the page; everything else,
+// and files whose location is already taken by a different file, goes to
+// assets/<hash>-<name>.
+func (b *Builder) collectAssets(pages []page) ([]asset, []missingAsset) {
+	formatter, _ := b.engine.(linkFormatter)
+	claimed := map[string]string{}
+
+	var (
+		assets  []asset
+		missing []missingAsset
+	)
+	for i := range pages {
+		from := pages[i]
+		sourceDir := filepath.Dir(from.record.Source)
+		resolve := func(link string, line int) string {
+			target, suffix, ok := splitRelativeLink(link)
+			if !ok || !isAssetPath(target) {
+				return link
+			}
+			source := filepath.Clean(filepath.Join(sourceDir, filepath.FromSlash(target)))
+			info, err := os.Stat(source)
+			if err != nil {
+				missing = append(missing, missingAsset{Source: from.record.Source, Line: line, Link: link})
+				return link
+			}
+			if info.IsDir() {
+				return link
+			}
+
+			rel := ""
+			if isWithin(source, sourceDir) {
+				if local, err := filepath.Rel(sourceDir, source); err == nil {
+					rel = path.Join(path.Dir(from.rel), filepath.ToSlash(local))
+				}
+			}
+			if owner, taken := claimed[rel]; rel == "" || taken && owner != source {
+				hashed, err := hashedAssetPath(source)
+				if err != nil {
+					missing = append(missing, missingAsset{Source: from.record.Source, Line: line, Link: link})
+					return link
+				}
+				rel = hashed
+			}
+			if _, taken := claimed[rel]; !taken {
+				claimed[rel] = source
+				assets = append(assets, asset{source: source, rel: rel})
+			}
+
+			rewritten := relativePageLink(from.rel, rel, "")
+			if formatter != nil {
+				rewritten = formatter.formatAsset(from, rel)
+			}
+			rewritten += suffix
+			if strings.HasPrefix(link, "<") {
+				return "<" + rewritten + ">"
+			}
+			return rewritten
+		}
+		pages[i].data = []byte(mapMarkdownLinks(string(from.data), resolve))
+	}
+
+	for _, m := range missing {
+		fmt.Fprintf(os.Stderr, "warning: missing asset %s\n", m)
+	}
+	return assets, missing
+}
+
+// isAssetPath reports whether target names a file to copy rather than a page:
+// it needs an extension, and links to markdown or HTML pages are left to the
+// link rewriter and the engine.
+func isAssetPath(target string) bool {
+	switch strings.ToLower(path.Ext(target)) {
+	case "", ".md", ".html", ".htm":
+		return false
+	}
+	return true
+}
+
+func hashedAssetPath(source string) (string, error) {
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	hash := strings.TrimPrefix(contentHash(data), "sha256:")
+	return path.Join(assetsDirName, hash[:12]+"-"+filepath.Base(source)), nil
+}
+
+// writeAssets copies the collected assets into the content directory.
+func (b *Builder) writeAssets(env environment, assets []asset) error {
+	for _, a := range assets {
+		target := filepath.Join(env.contentDir, filepath.FromSlash(a.rel))
+		if err := copyFile(a.source, target); err != nil {
+			return err
+		}
+		if b.cfg.Verbose {
+			fmt.Printf("  copied asset %s -> %s\n", a.source, target)
+		}
+	}
+	return nil
+}
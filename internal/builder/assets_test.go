@@ -0,0 +1,134 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCopiesReferencedAssets(t *testing.T) {
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "docs")
+
+	page := strings.Join([]string{
+		"---",
+		"category: architecture",
+		"---",
+		"# System Context",
+		"",
+		"![flow](./img/flow.png) and [spec](../shared/spec.pdf?raw=1).",
+		`<img src="img/flow.png" alt="flow">`,
+		"![gone](img/missing.png) `![code](img/code.png)`",
+		"",
+	}, "\n")
+	writeTestFile(t, filepath.Join(searchDir, "system", "DOC_System_Context.md"), page)
+	writeTestFile(t, filepath.Join(searchDir, "system", "img", "flow.png"), "flow")
+	writeTestFile(t, filepath.Join(searchDir, "shared", "spec.pdf"), "spec")
+	writeTestFile(t, filepath.Join(searchDir, "other", "DOC_Other.md"), "---\ncategory: architecture\n---\n![flow](img/flow.png)\n")
+	writeTestFile(t, filepath.Join(searchDir, "other", "img", "flow.png"), "other flow")
+
+	cfg := Config{Prefix: "DOC_", Engine: "mdbook", SearchPath: searchDir, DocDir: docDir, TempDirName: "temp"}
+	b := New(cfg)
+	b.engine = &mdbookEngine{cfg: cfg}
+	env := environment{docDir: docDir, searchRoot: searchDir, tempDir: filepath.Join(docDir, "temp"), contentDir: filepath.Join(docDir, "temp", "src"), sources: sourceMap{}}
+
+	pages, err := b.collectPrefixedDocs(env)
+	if err != nil {
+		t.Fatalf("collectPrefixedDocs returned error: %v", err)
+	}
+	assets, missing := b.collectAssets(pages)
+	if err := b.writeAssets(env, assets); err != nil {
+		t.Fatalf("writeAssets returned error: %v", err)
+	}
+
+	if len(missing) != 1 || missing[0].Link != "img/missing.png" || missing[0].Line != 8 {
+		t.Fatalf("unexpected missing assets: %+v", missing)
+	}
+
+	var context, other string
+	for _, p := range pages {
+		switch p.rel {
+		case "architecture/system-context.md":
+			context = string(p.data)
+		case "architecture/other.md":
+			other = string(p.data)
+		}
+	}
+
+	specHash, err := hashedAssetPath(filepath.Join(searchDir, "shared", "spec.pdf"))
+	if err != nil {
+		t.Fatalf("hashedAssetPath returned error: %v", err)
+	}
+	flowHash, err := hashedAssetPath(filepath.Join(searchDir, "system", "img", "flow.png"))
+	if err != nil {
+		t.Fatalf("hashedAssetPath returned error: %v", err)
+	}
+	// other/ is walked first, so its img/flow.png keeps the location next to
+	// the page and the different file of system/ moves to the hashed directory.
+	for _, expect := range []string{
+		"![flow](../" + flowHash + ")",
+		"[spec](../" + specHash + "?raw=1)",
+		`<img src="../` + flowHash + `" alt="flow">`,
+		"`![code](img/code.png)`",
+	} {
+		if !strings.Contains(context, expect) {
+			t.Fatalf("expected %q in page:\n%s", expect, context)
+		}
+	}
+	if !strings.Contains(other, "![flow](img/flow.png)") {
+		t.Fatalf("expected asset to stay next to the page:\n%s", other)
+	}
+
+	for rel, want := range map[string]string{
+		"architecture/img/flow.png": "other flow",
+		specHash:                    "spec",
+		flowHash:                    "flow",
+	} {
+		data, err := os.ReadFile(filepath.Join(env.contentDir, filepath.FromSlash(rel)))
+		if err != nil || string(data) != want {
+			t.Fatalf("expected %s to contain %q, got %q (%v)", rel, want, data, err)
+		}
+	}
+}
+
+func TestRunPublishesAssetsWithHTMLEngine(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Intro.md", "# Intro\n\n![logo](logo.svg)\n")
+	f.source("logo.svg", "<svg/>")
+	f.run()
+	if !f.published("guides/logo.svg") {
+		t.Fatalf("expected asset next to the published page")
+	}
+}
+
+func TestCollectAssetsIgnoresPageLinks(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "a", "DOC_One.md"), "")
+	writeTestFile(t, filepath.Join(root, "b", "DOC_Two.md"), "")
+
+	content := "[two](../b/DOC_Two.md#setup) [setup](./setup) [guide](../guide/) [page](intro.html)\n"
+	newPages := func() []page {
+		return []page{
+			{record: menuRecord{Source: filepath.Join(root, "a", "DOC_One.md")}, rel: "a/one.md", data: []byte(content)},
+			{record: menuRecord{Source: filepath.Join(root, "b", "DOC_Two.md")}, rel: "b/two.md", data: []byte("# Two\n")},
+		}
+	}
+	b := &Builder{engine: &hugoEngine{}}
+
+	pages := newPages()
+	assets, missing := b.collectAssets(pages)
+	b.rewriteLinks(pages)
+	if len(assets) != 0 || len(missing) != 0 {
+		t.Fatalf("expected no assets, got %+v and missing %+v", assets, missing)
+	}
+	if !strings.Contains(string(pages[0].data), `[two]({{< relref "/b/two.md#setup" >}})`) {
+		t.Fatalf("expected relref link, got %s", pages[0].data)
+	}
+
+	// Rewritten relref shortcodes are not mistaken for files either.
+	if _, missing := b.collectAssets(pages); len(missing) != 0 {
+		t.Fatalf("expected relref links to be ignored, got %+v", missing)
+	}
+}
@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	audienceIfPattern  = regexp.MustCompile(`^<!--\s*if\s+audience\s*(!?=)\s*(.*?)\s*-->$`)
+	audienceEndPattern = regexp.MustCompile(`^<!--\s*endif\s*-->$`)
+)
+
+func (b *Builder) selectAudience(pages []page) ([]page, []skippedPage, error) {
+	audience := strings.TrimSpace(b.cfg.Audience)
+	kept := make([]page, 0, len(pages))
+	var skipped []skippedPage
+	for _, p := range pages {
+		audiences := p.record.FrontMatter.Audience
+		if audience != "" && len(audiences) > 0 && !containsString(audiences, audience) {
+			skipped = append(skipped, skippedPage{
+				Source: p.record.Source,
+				Reason: fmt.Sprintf("audience %s, building for %s", strings.Join(audiences, ", "), audience),
+			})
+			continue
+		}
+
+		data, err := applyAudienceBlocks(p.record.Source, p.data, audience)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.data = data
+		kept = append(kept, p)
+	}
+	return kept, skipped, nil
+}
+
+// applyAudienceBlocks drops <!-- if audience=... --> blocks not matching audience.
+func applyAudienceBlocks(source string, content []byte, audience string) ([]byte, error) {
+	header, body := "", string(content)
+	if block, ok := splitFrontMatter(content); ok {
+		header, body = body[:block.End], body[block.End:]
+	}
+	offset := strings.Count(header, "\n")
+
+	type condition struct {
+		line    int
+		matches bool
+	}
+	var open []condition
+	visible := func() bool {
+		for _, c := range open {
+			if !c.matches {
+				return false
+			}
+		}
+		return true
+	}
+
+	var out strings.Builder
+	out.WriteString(header)
+	fence := ""
+	for i, line := range strings.SplitAfter(body, "\n") {
+		lineNo := offset + i + 1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case fenceMarker(line) != "":
+			fence = fenceMarker(line)
+		default:
+			if match := audienceIfPattern.FindStringSubmatch(trimmed); match != nil {
+				values := splitAudiences(match[2])
+				if len(values) == 0 {
+					return nil, fmt.Errorf("%s:%d: audience condition names no audience", source, lineNo)
+				}
+				matches := audience == "" || containsString(values, audience) == (match[1] == "=")
+				open = append(open, condition{line: lineNo, matches: matches})
+				continue
+			}
+			if audienceEndPattern.MatchString(trimmed) {
+				if len(open) == 0 {
+					return nil, fmt.Errorf("%s:%d: endif without a matching if", source, lineNo)
+				}
+				open = open[:len(open)-1]
+				continue
+			}
+		}
+		if visible() {
+			out.WriteString(line)
+		}
+	}
+	if len(open) > 0 {
+		return nil, fmt.Errorf("%s:%d: if audience block is not closed with <!-- endif -->", source, open[len(open)-1].line)
+	}
+	return []byte(out.String()), nil
+}
+
+func splitAudiences(value string) []string {
+	var audiences []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			audiences = append(audiences, part)
+		}
+	}
+	return audiences
+}
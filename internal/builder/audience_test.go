@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyAudienceBlocks(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Support",
+		"---",
+		"Everyone",
+		"<!-- if audience=internal -->",
+		"Internal only",
+		"<!-- if audience!=partner -->",
+		"Nested internal",
+		"<!-- endif -->",
+		"<!-- endif -->",
+		"<!-- if audience = public, partner -->",
+		"External",
+		"```html",
+		"<!-- if audience=internal -->",
+		"```",
+		"<!-- endif -->",
+		"",
+	}, "\n")
+
+	cases := map[string]string{
+		"internal": "---\ntitle: Support\n---\nEveryone\nInternal only\nNested internal\n",
+		"public":   "---\ntitle: Support\n---\nEveryone\nExternal\n```html\n<!-- if audience=internal -->\n```\n",
+		"": "---\ntitle: Support\n---\nEveryone\nInternal only\nNested internal\nExternal\n" +
+			"```html\n<!-- if audience=internal -->\n```\n",
+	}
+	for audience, want := range cases {
+		got, err := applyAudienceBlocks("page.md", []byte(content), audience)
+		if err != nil {
+			t.Fatalf("audience %q: unexpected error %v", audience, err)
+		}
+		if string(got) != want {
+			t.Fatalf("audience %q: got\n%s\nwant\n%s", audience, got, want)
+		}
+	}
+}
+
+func TestApplyAudienceBlocksErrors(t *testing.T) {
+	cases := map[string]string{
+		"---\ntitle: x\n---\ntext\n<!-- if audience=internal -->\nopen\n": "page.md:5: if audience block is not closed",
+		"text\n<!-- endif -->\n":                  "page.md:2: endif without a matching if",
+		"<!-- if audience= -->\n<!-- endif -->\n": "page.md:1: audience condition names no audience",
+	}
+	for content, want := range cases {
+		_, err := applyAudienceBlocks("page.md", []byte(content), "public")
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Fatalf("applyAudienceBlocks(%q) error = %v, want prefix %q", content, err, want)
+		}
+	}
+}
+
+func TestRunSelectsAudience(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Guide.md", "# Guide\n<!-- if audience=internal -->\nOn-call rota\n<!-- endif -->\n")
+	f.source("DOC_Runbook.md", "---\naudience: internal\n---\n# Runbook\n")
+	f.source("DOC_Pricing.md", "---\nvisibility: [public, partner]\n---\n# Pricing\n")
+
+	cases := map[string][]string{
+		"internal": {"guide", "runbook"},
+		"public":   {"guide", "pricing"},
+	}
+	for audience, want := range cases {
+		f.cfg.Audience = audience
+		f.run()
+
+		entries, err := os.ReadDir(filepath.Join(f.docDir, "dist", "guides"))
+		if err != nil {
+			t.Fatalf("audience %q: %v", audience, err)
+		}
+		var got []string
+		for _, entry := range entries {
+			got = append(got, strings.TrimSuffix(entry.Name(), ".html"))
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("audience %q: published %v, want %v", audience, got, want)
+		}
+
+		data := f.output("guides/guide.html")
+		if strings.Contains(data, "On-call rota") != (audience == "internal") {
+			t.Fatalf("audience %q: unexpected conditional block in:\n%s", audience, data)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package builder
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Category strategies accepted by CategoryInference.Strategy.
+const (
+	categoryFromFrontMatter = "frontmatter"
+	categoryFromDirectory   = "directory"
+	categoryFromMapping     = "mapping"
+)
+
+// defaultCategory is used for prefixed files whose category cannot be derived.
+const defaultCategory = "guides"
+
+var categoryStrategies = []string{categoryFromFrontMatter, categoryFromDirectory, categoryFromMapping}
+
+// CategoryInference decides the category of prefixed files without one.
+type CategoryInference struct {
+	// Strategy is frontmatter, directory or mapping.
+	Strategy string            `yaml:"strategy" json:"strategy"`
+	Default  string            `yaml:"default" json:"default"`
+	Strip    []string          `yaml:"strip" json:"strip"`
+	Rename   map[string]string `yaml:"rename" json:"rename"`
+	Mapping  []CategoryMapping `yaml:"mapping" json:"mapping"`
+}
+
+// CategoryMapping assigns Category to source paths matching Pattern.
+type CategoryMapping struct {
+	Pattern  string `yaml:"pattern" json:"pattern"`
+	Category string `yaml:"category" json:"category"`
+}
+
+func (c CategoryInference) validate() error {
+	if c.Strategy != "" && !containsString(categoryStrategies, c.Strategy) {
+		return fmt.Errorf("unsupported category strategy '%s': expected %s", c.Strategy, strings.Join(categoryStrategies, ", "))
+	}
+	for _, pattern := range c.Strip {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	for _, mapping := range c.Mapping {
+		if mapping.Pattern == "" {
+			return fmt.Errorf("category mapping for '%s' has no pattern", mapping.Category)
+		}
+		if err := validateGlob(mapping.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c CategoryInference) inferCategory(searchRoot, source string) string {
+	fallback := c.Default
+	if fallback == "" {
+		fallback = defaultCategory
+	}
+
+	rel, err := filepath.Rel(searchRoot, source)
+	if err != nil {
+		return fallback
+	}
+	rel = filepath.ToSlash(rel)
+
+	switch c.Strategy {
+	case categoryFromDirectory:
+		if category := c.directoryCategory(path.Dir(rel)); category != "" {
+			return category
+		}
+	case categoryFromMapping:
+		for _, mapping := range c.Mapping {
+			if matchGlob(mapping.Pattern, rel) {
+				return mapping.Category
+			}
+		}
+	}
+	return fallback
+}
+
+func (c CategoryInference) directoryCategory(dir string) string {
+	segments := splitPath(dir)
+	for _, pattern := range c.Strip {
+		patternSegments := splitPath(pattern)
+		if n, ok := matchPrefix(patternSegments, segments); ok {
+			segments = segments[n:]
+		}
+	}
+	dir = strings.Join(segments, "/")
+
+	prefixes := make([]string, 0, len(c.Rename))
+	for prefix := range c.Rename {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	for _, prefix := range prefixes {
+		from := normalizeCategoryPath(prefix)
+		if dir == from || strings.HasPrefix(dir, from+"/") {
+			dir = normalizeCategoryPath(c.Rename[prefix] + strings.TrimPrefix(dir, from))
+			break
+		}
+	}
+	return normalizeCategoryPath(dir)
+}
+
+// matchGlob is path.Match per segment, with ** matching any number of them.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(splitPath(pattern), splitPath(name))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func matchPrefix(pattern, name []string) (int, bool) {
+	for n := 1; n <= len(name); n++ {
+		if matchSegments(pattern, name[:n]) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func validateGlob(pattern string) error {
+	for _, segment := range splitPath(pattern) {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func splitPath(value string) []string {
+	value = strings.Trim(filepath.ToSlash(value), "/")
+	if value == "" || value == "." {
+		return nil
+	}
+	return strings.Split(value, "/")
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
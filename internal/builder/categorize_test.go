@@ -0,0 +1,72 @@
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInferCategoryStrategies(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	source := filepath.Join(root, "src", "platform", "mobile", "features", "DOC_Offline_Mode.md")
+
+	cases := []struct {
+		name      string
+		inference CategoryInference
+		want      string
+	}{
+		{"frontmatter", CategoryInference{}, "guides"},
+		{"custom default", CategoryInference{Default: "misc"}, "misc"},
+		{"directory", CategoryInference{Strategy: categoryFromDirectory}, "src/platform/mobile/features"},
+		{"directory strip", CategoryInference{Strategy: categoryFromDirectory, Strip: []string{"src"}}, "platform/mobile/features"},
+		{"directory strip glob", CategoryInference{Strategy: categoryFromDirectory, Strip: []string{"**/mobile"}}, "features"},
+		{"directory rename", CategoryInference{
+			Strategy: categoryFromDirectory,
+			Strip:    []string{"src"},
+			Rename:   map[string]string{"platform": "apps", "platform/mobile": "mobile"},
+		}, "mobile/features"},
+		{"mapping", CategoryInference{Strategy: categoryFromMapping, Mapping: []CategoryMapping{
+			{Pattern: "src/platform/web/**", Category: "web"},
+			{Pattern: "src/**/mobile/**/DOC_*.md", Category: "mobile"},
+		}}, "mobile"},
+		{"mapping fallback", CategoryInference{Strategy: categoryFromMapping, Mapping: []CategoryMapping{
+			{Pattern: "docs/**", Category: "docs"},
+		}}, "guides"},
+	}
+	for _, tc := range cases {
+		if got := tc.inference.inferCategory(root, source); got != tc.want {
+			t.Fatalf("%s: inferCategory = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	atRoot := CategoryInference{Strategy: categoryFromDirectory}
+	if got := atRoot.inferCategory(root, filepath.Join(root, "DOC_Readme.md")); got != "guides" {
+		t.Fatalf("expected files in the search root to use the default, got %q", got)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := map[string]bool{
+		"**/*.md|a/b/c.md":       true,
+		"**|a/b":                 true,
+		"a/**/c.md|a/c.md":       true,
+		"a/**/c.md|a/x/y/c.md":   true,
+		"a/*/c.md|a/x/y/c.md":    false,
+		"a/**/DOC_*.md|b/DOC.md": false,
+	}
+	for input, want := range cases {
+		pattern, name, _ := strings.Cut(input, "|")
+		if got := matchGlob(pattern, name); got != want {
+			t.Fatalf("matchGlob(%q, %q) = %v, want %v", pattern, name, got, want)
+		}
+	}
+}
+
+func TestCategoryInferenceValidate(t *testing.T) {
+	if err := (CategoryInference{Strategy: "guess"}).validate(); err == nil || !strings.Contains(err.Error(), "unsupported category strategy") {
+		t.Fatalf("expected strategy error, got %v", err)
+	}
+	if err := (CategoryInference{Mapping: []CategoryMapping{{Pattern: "src/[", Category: "x"}}}).validate(); err == nil {
+		t.Fatalf("expected invalid pattern error")
+	}
+}
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// categoryFileNames are the metadata files read from category directories of
+// the documentation directory.
+var categoryFileNames = []string{"_category.yaml", "_category.yml"}
+
+// CategoryConfig describes how a category is shown in the navigation. Unset
+// fields keep the defaults: a title derived from the directory name,
+// alphabetical position and a collapsed group.
+type CategoryConfig struct {
+	Title string `yaml:"title" json:"title"`
+	// Order positions the category among its siblings; categories without
+	// one follow in alphabetical order.
+	Order *int   `yaml:"order" json:"order"`
+	Icon  string `yaml:"icon" json:"icon"`
+	// Collapsed controls whether the group starts closed, true when unset.
+	Collapsed *bool `yaml:"collapsed" json:"collapsed"`
+}
+
+// merge returns c with every field that is set in override replaced.
+func (c CategoryConfig) merge(override CategoryConfig) CategoryConfig {
+	if override.Title != "" {
+		c.Title = override.Title
+	}
+	if override.Order != nil {
+		c.Order = override.Order
+	}
+	if override.Icon != "" {
+		c.Icon = override.Icon
+	}
+	if override.Collapsed != nil {
+		c.Collapsed = override.Collapsed
+	}
+	return c
+}
+
+// loadCategories collects category metadata from _category.yaml files in the
+// documentation directory and the categories section of the config, which
+// wins field by field. Keys are normalized category paths.
+func (b *Builder) loadCategories(env environment) (map[string]CategoryConfig, error) {
+	categories := map[string]CategoryConfig{}
+	err := b.walkDocDir(env, func(path, rel string) error {
+		name := filepath.Base(rel)
+		isCategoryFile := false
+		for _, candidate := range categoryFileNames {
+			if name == candidate {
+				isCategoryFile = true
+			}
+		}
+		if !isCategoryFile {
+			return nil
+		}
+
+		meta, err := readCategoryFile(path)
+		if err != nil {
+			return err
+		}
+		key := normalizeCategoryPath(filepath.ToSlash(filepath.Dir(rel)))
+		categories[key] = categories[key].merge(meta)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key, meta := range b.cfg.Categories {
+		key = normalizeCategoryPath(key)
+		categories[key] = categories[key].merge(meta)
+	}
+	return categories, nil
+}
+
+func readCategoryFile(path string) (CategoryConfig, error) {
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CategoryConfig{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var meta CategoryConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&meta); err != nil && !errors.Is(err, io.EOF) {
+		return CategoryConfig{}, fmt.Errorf("failed to parse category metadata %s: %w", path, err)
+	}
+	return meta, nil
+}
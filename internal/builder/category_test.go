@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCategoriesMergesFilesAndConfig(t *testing.T) {
+	docDir := t.TempDir()
+	writeTestFile(t, filepath.Join(docDir, "guides", "_category.yaml"), "title: Guides\norder: 2\nicon: book\ncollapsed: false\n")
+	writeTestFile(t, filepath.Join(docDir, "temp", "skipped", "_category.yaml"), "title: Skipped\n")
+
+	order := 1
+	cfg := Config{TempDirName: "temp", Categories: map[string]CategoryConfig{
+		"/guides/": {Order: &order},
+		"api":      {Title: "API"},
+	}}
+	b := &Builder{cfg: cfg, engine: &htmlEngine{cfg: cfg}}
+	env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")}
+
+	categories, err := b.loadCategories(env)
+	if err != nil {
+		t.Fatalf("loadCategories returned error: %v", err)
+	}
+	guides := categories["guides"]
+	if guides.Title != "Guides" || guides.Icon != "book" || guides.Order == nil || *guides.Order != 1 || guides.Collapsed == nil || *guides.Collapsed {
+		t.Fatalf("unexpected guides metadata %+v", guides)
+	}
+	if categories["api"].Title != "API" {
+		t.Fatalf("expected config category, got %+v", categories["api"])
+	}
+	if _, ok := categories["skipped"]; ok {
+		t.Fatalf("expected workspace metadata to be ignored")
+	}
+}
+
+func TestLoadCategoriesRejectsUnknownFields(t *testing.T) {
+	docDir := t.TempDir()
+	writeTestFile(t, filepath.Join(docDir, "guides", "_category.yml"), "label: Guides\n")
+
+	cfg := Config{TempDirName: "temp"}
+	b := &Builder{cfg: cfg, engine: &htmlEngine{cfg: cfg}}
+	_, err := b.loadCategories(environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")})
+	if err == nil || !strings.Contains(err.Error(), "field label not found") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
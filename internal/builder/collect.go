@@ -1,7 +1,6 @@
 package builder
 
 import (
-	"bufio"
 	"fmt"
 	"io/fs"
 	"os"
@@ -9,19 +8,42 @@ import (
 	"strings"
 )
 
+// Origins of a collected page.
+const (
+	originPrefixed = "prefixed"
+	originExisting = "existing"
+)
+
 type menuRecord struct {
 	CategoryPath string
 	Slug         string
 	Title        string
+	// Source is the absolute path of the original markdown file.
+	Source string
+	// Origin tells whether the page was found by prefix or merged from the
+	// documentation directory.
+	Origin      string
+	Hash        string
+	FrontMatter frontMatter
 }
 
-func (b *Builder) collectPrefixedDocs(env environment, recordSet map[string]struct{}) ([]menuRecord, int, error) {
+// page is a collected markdown file waiting to be written into the workspace.
+type page struct {
+	record menuRecord
+	// rel is the slash separated target path relative to the content directory.
+	rel  string
+	data []byte
+	// home marks the root index.md, which is published but not listed in the
+	// navigation.
+	home bool
+}
+
+func (b *Builder) collectPrefixedDocs(env environment) ([]page, error) {
 	if b.cfg.Verbose {
 		fmt.Printf("[2/7] Scanning %s for files starting with %s\n", env.searchRoot, b.cfg.Prefix)
 	}
 
-	var menuRecords []menuRecord
-	count := 0
+	var pages []page
 
 	err := filepath.WalkDir(env.searchRoot, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
@@ -53,78 +75,55 @@ func (b *Builder) collectPrefixedDocs(env environment, recordSet map[string]stru
 			return fmt.Errorf("failed to read %s: %w", path, err)
 		}
 
-		count++
-		fm := parseFrontMatter(data)
-		category := strings.TrimSpace(fm["category"])
+		fm, err := parseFrontMatter(path, data)
+		if err != nil {
+			return err
+		}
+		category := fm.Category
 		if category == "" {
-			category = "guides"
+			category = b.cfg.CategoryInference.inferCategory(env.searchRoot, path)
 		}
 		categoryPath := normalizeCategoryPath(category)
 		slug := buildSlug(base, b.cfg.Prefix)
-		title := deriveTitle(data, fm["title"], slug, b.cfg.Prefix)
-
-		targetDir := filepath.Join(env.tempDir, filepath.FromSlash(categoryPath))
-		if err := os.MkdirAll(targetDir, 0o755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", targetDir, err)
-		}
-		targetFile := filepath.Join(targetDir, slug+".md")
-		if err := os.WriteFile(targetFile, data, 0o644); err != nil {
-			return fmt.Errorf("failed to copy %s to %s: %w", path, targetFile, err)
-		}
-
-		key := menuKey(categoryPath, slug)
-		if _, exists := recordSet[key]; !exists {
-			recordSet[key] = struct{}{}
-			menuRecords = append(menuRecords, menuRecord{CategoryPath: categoryPath, Slug: slug, Title: title})
-		}
-
-		if b.cfg.Verbose {
-			fmt.Printf("  collected %s -> %s\n", path, targetFile)
-		}
+		title := deriveTitle(data, fm.Title, slug, b.cfg.Prefix)
+		if fm.Slug != "" {
+			slug = fm.Slug
+		}
+
+		pages = append(pages, page{
+			record: menuRecord{
+				CategoryPath: categoryPath,
+				Slug:         slug,
+				Title:        title,
+				Source:       path,
+				Origin:       originPrefixed,
+				Hash:         contentHash(data),
+				FrontMatter:  fm,
+			},
+			rel:  strings.TrimPrefix(categoryPath+"/"+slug+".md", "/"),
+			data: data,
+		})
 		return nil
 	})
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return menuRecords, count, nil
+	return pages, nil
 }
 
-func (b *Builder) collectExistingDocs(env environment, recordSet map[string]struct{}) ([]menuRecord, int, error) {
+func (b *Builder) collectExistingDocs(env environment) ([]page, error) {
 	if b.cfg.Verbose {
 		fmt.Printf("[3/7] Merging existing documentation from %s\n", env.docDir)
 	}
 
-	var menuRecords []menuRecord
-	count := 0
-
-	err := filepath.WalkDir(env.docDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
-
-		rel, err := filepath.Rel(env.docDir, path)
-		if err != nil {
-			return err
-		}
-
-		if rel == "." {
-			return nil
-		}
-
-		if d.IsDir() {
-			base := d.Name()
-			if base == b.cfg.TempDirName || base == ".vitepress" || base == "node_modules" {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
+	var pages []page
+	err := b.walkDocDir(env, func(path, rel string) error {
 		if filepath.Ext(rel) != ".md" {
 			return nil
 		}
 
-		if rel == "index.md" || rel == "DOC_BUILD_README.md" {
+		if rel == "DOC_BUILD_README.md" {
 			return nil
 		}
 
@@ -134,69 +133,123 @@ func (b *Builder) collectExistingDocs(env environment, recordSet map[string]stru
 			return fmt.Errorf("failed to read %s: %w", path, err)
 		}
 
-		count++
+		fm, err := parseFrontMatter(path, data)
+		if err != nil {
+			return err
+		}
+		if rel == "index.md" {
+			pages = append(pages, page{
+				record: menuRecord{
+					Slug:        "index",
+					Title:       deriveTitle(data, fm.Title, "index", ""),
+					Source:      path,
+					Origin:      originExisting,
+					Hash:        contentHash(data),
+					FrontMatter: fm,
+				},
+				rel:  "index.md",
+				data: data,
+				home: true,
+			})
+			return nil
+		}
+
 		category := normalizeCategoryPath(filepath.ToSlash(filepath.Dir(rel)))
 		slug := strings.TrimSuffix(filepath.Base(rel), ".md")
 		title := deriveTitle(data, "", slug, "")
 		if slug == "index" && title != "" {
 			title = fmt.Sprintf("%s (overview)", title)
 		}
+		target := filepath.ToSlash(rel)
+		if fm.Slug != "" {
+			slug = fm.Slug
+			target = strings.TrimPrefix(category+"/"+slug+".md", "/")
+		}
+
+		pages = append(pages, page{
+			record: menuRecord{
+				CategoryPath: category,
+				Slug:         slug,
+				Title:        title,
+				Source:       path,
+				Origin:       originExisting,
+				Hash:         contentHash(data),
+				FrontMatter:  fm,
+			},
+			rel:  target,
+			data: data,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		targetPath := filepath.Join(env.tempDir, filepath.FromSlash(rel))
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(targetPath), err)
+	return pages, nil
+}
+
+// walkDocDir calls fn for every file of the documentation directory, skipping
+// the workspace, dependencies, the published output and engine owned
+// directories. rel is relative to the documentation directory.
+func (b *Builder) walkDocDir(env environment, fn func(path, rel string) error) error {
+	_, published := b.engine.Output(env)
+	return filepath.WalkDir(env.docDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
 		}
-		if err := os.WriteFile(targetPath, data, 0o644); err != nil {
-			return fmt.Errorf("failed to copy %s to %s: %w", path, targetPath, err)
+
+		rel, err := filepath.Rel(env.docDir, path)
+		if err != nil {
+			return err
 		}
 
-		key := menuKey(category, slug)
-		if _, exists := recordSet[key]; !exists {
-			recordSet[key] = struct{}{}
-			menuRecords = append(menuRecords, menuRecord{CategoryPath: category, Slug: slug, Title: title})
+		if rel == "." {
+			return nil
 		}
 
-		if b.cfg.Verbose {
-			fmt.Printf("  merged %s\n", rel)
+		if d.IsDir() {
+			base := d.Name()
+			if base == b.cfg.TempDirName || base == "node_modules" || isWithin(path, published) {
+				return filepath.SkipDir
+			}
+			for _, reserved := range env.reservedDirs {
+				if isWithin(path, reserved) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
 		}
-		return nil
+		return fn(path, rel)
 	})
-	if err != nil {
-		return nil, 0, err
-	}
+}
 
-	indexPath := filepath.Join(env.docDir, "index.md")
-	if _, err := os.Stat(indexPath); err == nil {
-		data, readErr := os.ReadFile(indexPath)
-		if readErr != nil {
-			return nil, 0, fmt.Errorf("failed to read %s: %w", indexPath, readErr)
+// writePages copies the resolved pages into the content directory and records
+// their sources for error mapping.
+func (b *Builder) writePages(env environment, pages []page) error {
+	for _, p := range pages {
+		target := filepath.Join(env.contentDir, filepath.FromSlash(p.rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
 		}
-		target := filepath.Join(env.tempDir, "index.md")
-		if writeErr := os.WriteFile(target, data, 0o644); writeErr != nil {
-			return nil, 0, fmt.Errorf("failed to copy %s to %s: %w", indexPath, target, writeErr)
+		if err := os.WriteFile(target, p.data, 0o644); err != nil {
+			return fmt.Errorf("failed to copy %s to %s: %w", p.record.Source, target, err)
 		}
-	}
+		env.sources.record(env, target, p.record.Source)
 
-	return menuRecords, count, nil
-}
-
-func (b *Builder) writeMenuIndex(env environment, records []menuRecord) error {
-	menuPath := filepath.Join(env.tempDir, ".menu-items.txt")
-	file, err := os.Create(menuPath)
-	if err != nil {
-		return fmt.Errorf("failed to create %s: %w", menuPath, err)
+		if b.cfg.Verbose {
+			fmt.Printf("  collected %s -> %s\n", p.record.Source, target)
+		}
 	}
-	defer file.Close()
+	return nil
+}
 
-	writer := bufio.NewWriter(file)
-	for _, rec := range records {
-		line := fmt.Sprintf("%s|%s|%s\n", rec.CategoryPath, rec.Slug, rec.Title)
-		if _, err := writer.WriteString(line); err != nil {
-			return fmt.Errorf("failed to write menu entry to %s: %w", menuPath, err)
+// navigationRecords returns the records of every page listed in the navigation.
+func navigationRecords(pages []page) []menuRecord {
+	records := make([]menuRecord, 0, len(pages))
+	for _, p := range pages {
+		if !p.home && !p.record.FrontMatter.Hidden {
+			records = append(records, p.record)
 		}
 	}
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush %s: %w", menuPath, err)
-	}
-	return nil
+	return records
 }
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Collision policies accepted by Config.OnCollision.
+const (
+	collisionError    = "error"
+	collisionWarn     = "warn"
+	collisionPrefixed = "prefixed"
+	collisionExisting = "existing"
+)
+
+var collisionPolicies = []string{collisionError, collisionWarn, collisionPrefixed, collisionExisting}
+
+// collision describes two sources that resolve to the same workspace page.
+type collision struct {
+	// Path is the page path relative to the content directory.
+	Path   string
+	Kept   menuRecord
+	Shadow menuRecord
+	// ByRule is set when the precedence policy decided which page wins.
+	ByRule bool
+}
+
+func (c collision) String() string {
+	return fmt.Sprintf("%s: using %s, ignoring %s", c.Path, displayPath(c.Kept.Source), displayPath(c.Shadow.Source))
+}
+
+// resolveCollisions keeps one page per category and slug according to the
+// configured policy. With "prefixed" or "existing" a page of the preferred
+// origin replaces the other one; collisions between pages of the same origin
+// always keep the first page found. Every collision is returned, and with the
+// "error" policy they are reported as a single error instead.
+func (b *Builder) resolveCollisions(pages []page) ([]page, []collision, error) {
+	policy := b.cfg.OnCollision
+	if policy == "" {
+		policy = collisionWarn
+	}
+
+	index := map[string]int{}
+	resolved := make([]page, 0, len(pages))
+	var collisions []collision
+	for _, p := range pages {
+		key := p.rel
+		pos, exists := index[key]
+		if !exists {
+			index[key] = len(resolved)
+			resolved = append(resolved, p)
+			continue
+		}
+
+		current := resolved[pos]
+		// The precedence policies are named after the origin they prefer.
+		if p.record.Origin != current.record.Origin &&
+			(policy == collisionPrefixed || policy == collisionExisting) {
+			if p.record.Origin == policy {
+				resolved[pos] = p
+				collisions = append(collisions, collision{Path: key, Kept: p.record, Shadow: current.record, ByRule: true})
+			} else {
+				collisions = append(collisions, collision{Path: key, Kept: current.record, Shadow: p.record, ByRule: true})
+			}
+			continue
+		}
+		collisions = append(collisions, collision{Path: key, Kept: current.record, Shadow: p.record})
+	}
+
+	if policy == collisionError && len(collisions) > 0 {
+		lines := make([]string, 0, len(collisions))
+		for _, c := range collisions {
+			lines = append(lines, fmt.Sprintf("  %s is provided by %s and %s", c.Path, displayPath(c.Kept.Source), displayPath(c.Shadow.Source)))
+		}
+		return nil, collisions, fmt.Errorf("found %d slug collisions:\n%s", len(collisions), strings.Join(lines, "\n"))
+	}
+	for _, c := range collisions {
+		if !c.ByRule {
+			fmt.Fprintf(os.Stderr, "warning: slug collision %s\n", c)
+		} else if b.cfg.Verbose {
+			fmt.Printf("  slug collision %s (on-collision: %s)\n", c, policy)
+		}
+	}
+	return resolved, collisions, nil
+}
+
+func validCollisionPolicy(policy string) bool {
+	if policy == "" {
+		return true
+	}
+	for _, candidate := range collisionPolicies {
+		if policy == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func collisionPages() []page {
+	return []page{
+		{record: menuRecord{CategoryPath: "guides", Slug: "setup", Source: "/repo/a/DOC_Setup.md", Origin: originPrefixed}, rel: "guides/setup.md", data: []byte("a")},
+		{record: menuRecord{CategoryPath: "guides", Slug: "setup", Source: "/repo/b/DOC_Setup.md", Origin: originPrefixed}, rel: "guides/setup.md", data: []byte("b")},
+		{record: menuRecord{CategoryPath: "api", Slug: "intro", Source: "/repo/DOC_Intro.md", Origin: originPrefixed}, rel: "api/intro.md", data: []byte("c")},
+		{record: menuRecord{CategoryPath: "api", Slug: "intro", Source: "/repo/docs/api/intro.md", Origin: originExisting}, rel: "api/intro.md", data: []byte("d")},
+	}
+}
+
+func TestResolveCollisionsPolicies(t *testing.T) {
+	cases := map[string][]string{
+		"":                {"a", "c"},
+		collisionWarn:     {"a", "c"},
+		collisionPrefixed: {"a", "c"},
+		collisionExisting: {"a", "d"},
+	}
+	for policy, want := range cases {
+		b := &Builder{cfg: Config{OnCollision: policy}}
+		pages, collisions, err := b.resolveCollisions(collisionPages())
+		if err != nil {
+			t.Fatalf("policy %q: unexpected error %v", policy, err)
+		}
+		if len(collisions) != 2 {
+			t.Fatalf("policy %q: expected 2 collisions, got %d", policy, len(collisions))
+		}
+		var got []string
+		for _, p := range pages {
+			got = append(got, string(p.data))
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("policy %q: kept %v, want %v", policy, got, want)
+		}
+	}
+}
+
+func TestResolveCollisionsErrorPolicy(t *testing.T) {
+	b := &Builder{cfg: Config{OnCollision: collisionError}}
+	_, _, err := b.resolveCollisions(collisionPages())
+	if err == nil {
+		t.Fatalf("expected collision error")
+	}
+	for _, expect := range []string{"found 2 slug collisions", "guides/setup.md is provided by", "repo/a/DOC_Setup.md", "repo/b/DOC_Setup.md", "repo/docs/api/intro.md"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Fatalf("expected %q in error, got %v", expect, err)
+		}
+	}
+}
+
+func TestRunKeepsContentAndNavigationInSync(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Setup.md", "# Prefixed Setup\n")
+	f.doc("guides/setup.md", "# Curated Setup\n")
+	f.cfg.OnCollision = collisionExisting
+	f.run()
+
+	data := f.output("guides/setup.html")
+	if !strings.Contains(data, "Curated Setup") || strings.Contains(data, "Prefixed Setup") {
+		t.Fatalf("expected curated page to win, got:\n%s", data)
+	}
+
+	f.cfg.OnCollision = "random"
+	if err := New(f.cfg).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "unsupported collision policy") {
+		t.Fatalf("expected unsupported policy error, got %v", err)
+	}
+}
@@ -3,16 +3,64 @@ package builder
 import "context"
 
 type Config struct {
-	Prefix      string
-	Engine      string
-	SearchPath  string
-	DocDir      string
-	TempDirName string
-	Verbose     bool
+	Prefix      string `yaml:"prefix" json:"prefix"`
+	Engine      string `yaml:"engine" json:"engine"`
+	SearchPath  string `yaml:"search" json:"search"`
+	DocDir      string `yaml:"doc-dir" json:"doc-dir"`
+	TempDirName string `yaml:"temp-dir" json:"temp-dir"`
+	Verbose     bool   `yaml:"verbose" json:"verbose"`
+	// CacheDir stores installed node_modules keyed by the dependency
+	// manifests. Defaults to doc-builder inside the user cache directory.
+	CacheDir string `yaml:"cache-dir" json:"cache-dir"`
+	// PackageManager forces npm, pnpm, yarn or bun instead of detecting it
+	// from package.json and the lockfile.
+	PackageManager string `yaml:"package-manager" json:"package-manager"`
+	// OnCollision decides what happens when two sources map to the same page:
+	// error, warn (keep the first one, default), prefixed or existing.
+	OnCollision string `yaml:"on-collision" json:"on-collision"`
+	// IncludeDrafts publishes pages marked draft: true, which are skipped by
+	// default.
+	IncludeDrafts bool `yaml:"include-drafts" json:"include-drafts"`
+	// Audience selects the pages and conditional blocks written for one
+	// audience, such as internal or public. Empty includes everything.
+	Audience string `yaml:"audience" json:"audience"`
+	// CategoryInference derives categories for prefixed files whose front
+	// matter has none.
+	CategoryInference CategoryInference `yaml:"category-inference" json:"category-inference"`
+	// Categories sets navigation metadata per category path, overriding
+	// values from _category.yaml files.
+	Categories map[string]CategoryConfig `yaml:"categories" json:"categories"`
+
+	// APIReference generates reference pages for the Go packages below the
+	// search path.
+	APIReference APIReferenceConfig `yaml:"api-reference" json:"api-reference"`
+
+	MkDocs MkDocsConfig `yaml:"mkdocs" json:"mkdocs"`
+	MdBook MdBookConfig `yaml:"mdbook" json:"mdbook"`
+	Hugo   HugoConfig   `yaml:"hugo" json:"hugo"`
+}
+
+// MkDocsConfig holds the options of the mkdocs engine.
+type MkDocsConfig struct {
+	// Binary is the mkdocs executable, "mkdocs" from PATH when empty.
+	Binary string `yaml:"binary" json:"binary"`
+}
+
+// MdBookConfig holds the options of the mdbook engine.
+type MdBookConfig struct {
+	// Binary is the mdbook executable, "mdbook" from PATH when empty.
+	Binary string `yaml:"binary" json:"binary"`
+}
+
+// HugoConfig holds the options of the hugo engine.
+type HugoConfig struct {
+	// Binary is the hugo executable, "hugo" from PATH when empty.
+	Binary string `yaml:"binary" json:"binary"`
 }
 
 type Builder struct {
-	cfg Config
+	cfg    Config
+	engine Engine
 }
 
 func New(cfg Config) *Builder {
@@ -24,47 +72,83 @@ func (b *Builder) Run(ctx context.Context) error {
 		return err
 	}
 
-	env, err := b.prepareEnvironment()
+	engine, err := newEngine(b.cfg)
 	if err != nil {
 		return err
 	}
+	b.engine = engine
 
-	if err := b.prepareWorkspace(env); err != nil {
+	env, err := b.prepareEnvironment(engine)
+	if err != nil {
 		return err
 	}
 
-	menuRecords := make([]menuRecord, 0, 128)
-	recordSet := make(map[string]struct{})
+	if err := b.prepareWorkspace(env); err != nil {
+		return err
+	}
 
-	prefixedRecords, prefCount, err := b.collectPrefixedDocs(env, recordSet)
+	prefixed, err := b.collectPrefixedDocs(env)
+	if err != nil {
+		return err
+	}
+	existing, err := b.collectExistingDocs(env)
 	if err != nil {
 		return err
 	}
-	menuRecords = append(menuRecords, prefixedRecords...)
 
-	existingRecords, existingCount, err := b.collectExistingDocs(env, recordSet)
+	generated, err := b.generateAPIReference(env)
 	if err != nil {
 		return err
 	}
-	menuRecords = append(menuRecords, existingRecords...)
 
+	collected, skipped := b.excludeDrafts(append(append(prefixed, existing...), generated...))
+	if err := b.expandIncludes(collected); err != nil {
+		return err
+	}
+	collected, otherAudiences, err := b.selectAudience(collected)
+	if err != nil {
+		return err
+	}
+	skipped = append(skipped, otherAudiences...)
+	pages, collisions, err := b.resolveCollisions(collected)
+	if err != nil {
+		return err
+	}
+	b.revealHiddenPages(pages)
+	menuRecords := navigationRecords(pages)
 	if len(menuRecords) == 0 {
 		return errNoSources
 	}
-
-	if err := b.writeMenuIndex(env, menuRecords); err != nil {
+	redirects, err := b.collectRedirects(pages)
+	if err != nil {
 		return err
 	}
 
-	if err := b.generateConfig(env, menuRecords); err != nil {
+	assets, missingAssets := b.collectAssets(pages)
+	unresolved := b.rewriteLinks(pages)
+
+	if err := b.writePages(env, pages); err != nil {
+		return err
+	}
+	if err := b.writeAssets(env, assets); err != nil {
+		return err
+	}
+	if err := b.writeManifest(env, pages); err != nil {
 		return err
 	}
 
-	if err := b.installDependencies(ctx, env); err != nil {
+	categories, err := b.loadCategories(env)
+	if err != nil {
+		return err
+	}
+	if err := engine.RenderNavigation(env, buildSections(menuRecords, categories)); err != nil {
 		return err
 	}
 
-	if err := b.buildSite(ctx, env); err != nil {
+	if err := engine.Build(ctx, env); err != nil {
+		return err
+	}
+	if err := b.writeRedirects(env, redirects); err != nil {
 		return err
 	}
 
@@ -72,6 +156,16 @@ func (b *Builder) Run(ctx context.Context) error {
 		return err
 	}
 
-	b.printSummary(env, prefCount, existingCount, len(menuRecords))
+	b.printSummary(env, buildStats{
+		prefixed:        len(prefixed),
+		existing:        len(existing),
+		generated:       len(generated),
+		entries:         len(menuRecords),
+		collisions:      len(collisions),
+		unresolvedLinks: len(unresolved),
+		missingAssets:   len(missingAssets),
+		redirects:       len(redirects),
+		skipped:         append(skipped, hiddenPages(pages)...),
+	})
 	return nil
 }
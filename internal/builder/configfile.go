@@ -0,0 +1,247 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames lists the project config files probed inside the
+// documentation directory, in order of preference.
+var configFileNames = []string{"docbuilder.yaml", "docbuilder.yml", "docbuilder.json"}
+
+// FindConfigFile returns the first project config file found in dir, or an
+// empty string when the directory does not contain one.
+func FindConfigFile(dir string) (string, error) {
+	for _, name := range configFileNames {
+		candidate := filepath.Join(dir, name)
+		info, err := os.Stat(candidate)
+		if err == nil {
+			if info.IsDir() {
+				return "", fmt.Errorf("config path is a directory: %s", candidate)
+			}
+			return candidate, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to access config file %s: %w", candidate, err)
+		}
+	}
+	return "", nil
+}
+
+// LoadConfigFile reads a YAML or JSON project config and applies the values it
+// defines on top of base. Relative paths in the file are resolved against the
+// directory that contains it.
+func LoadConfigFile(path string, base Config) (Config, error) {
+	//nolint:gosec // config path is provided by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{}, fmt.Errorf("config file not found: %s", path)
+		}
+		return Config{}, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	isJSON := strings.EqualFold(filepath.Ext(path), ".json")
+
+	raw := map[string]any{}
+	if isJSON {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if unknown := unknownConfigKeys(raw, reflect.TypeOf(Config{}), ""); len(unknown) > 0 {
+		return Config{}, fmt.Errorf("unknown keys in config file %s: %s", path, strings.Join(unknown, ", "))
+	}
+	if invalid := invalidConfigValues(raw, reflect.TypeOf(Config{}), ""); len(invalid) > 0 {
+		return Config{}, fmt.Errorf("invalid values in config file %s: %s", path, strings.Join(invalid, ", "))
+	}
+
+	cfg := base
+	if isJSON {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if _, ok := raw["search"]; ok {
+		cfg.SearchPath = resolveConfigPath(dir, cfg.SearchPath)
+	}
+	if _, ok := raw["doc-dir"]; ok {
+		cfg.DocDir = resolveConfigPath(dir, cfg.DocDir)
+	}
+	if _, ok := raw["cache-dir"]; ok {
+		cfg.CacheDir = resolveConfigPath(dir, cfg.CacheDir)
+	}
+
+	return cfg, nil
+}
+
+func resolveConfigPath(dir, value string) string {
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+	return filepath.Join(dir, value)
+}
+
+// unknownConfigKeys walks the decoded document alongside the struct type and
+// returns the dotted paths of all keys that do not map to a field.
+func unknownConfigKeys(raw map[string]any, t reflect.Type, prefix string) []string {
+	fields := configFields(t)
+	var unknown []string
+	for key, value := range raw {
+		fieldType, ok := fields[key]
+		if !ok {
+			unknown = append(unknown, prefix+key)
+			continue
+		}
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		unknown = append(unknown, unknownNestedKeys(value, fieldType, prefix+key)...)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func unknownNestedKeys(value any, t reflect.Type, path string) []string {
+	switch t.Kind() {
+	case reflect.Struct:
+		if nested, ok := value.(map[string]any); ok {
+			return unknownConfigKeys(nested, t, path+".")
+		}
+	case reflect.Map:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		nested, ok := value.(map[string]any)
+		if !ok || elem.Kind() != reflect.Struct {
+			return nil
+		}
+		var unknown []string
+		for key, item := range nested {
+			unknown = append(unknown, unknownNestedKeys(item, elem, path+"."+key)...)
+		}
+		return unknown
+	case reflect.Slice:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		items, ok := value.([]any)
+		if !ok || elem.Kind() != reflect.Struct {
+			return nil
+		}
+		var unknown []string
+		for i, item := range items {
+			unknown = append(unknown, unknownNestedKeys(item, elem, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return unknown
+	}
+	return nil
+}
+
+// invalidConfigValues returns a message for every value whose type does not
+// fit its field, such as a boolean given for a whole section.
+func invalidConfigValues(raw map[string]any, t reflect.Type, prefix string) []string {
+	fields := configFields(t)
+	var invalid []string
+	for key, value := range raw {
+		if fieldType, ok := fields[key]; ok {
+			invalid = append(invalid, invalidConfigValue(value, fieldType, prefix+key)...)
+		}
+	}
+	sort.Strings(invalid)
+	return invalid
+}
+
+func invalidConfigValue(value any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return []string{path + " must be a mapping"}
+		}
+		return invalidConfigValues(nested, t, path+".")
+	case reflect.Map:
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return []string{path + " must be a mapping"}
+		}
+		var invalid []string
+		for key, item := range nested {
+			invalid = append(invalid, invalidConfigValue(item, t.Elem(), path+"."+key)...)
+		}
+		return invalid
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return []string{path + " must be a list"}
+		}
+		var invalid []string
+		for i, item := range items {
+			invalid = append(invalid, invalidConfigValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return invalid
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return []string{path + " must be true or false"}
+		}
+	case reflect.Int, reflect.Int64:
+		switch v := value.(type) {
+		case int, int64, uint64:
+		case float64:
+			if v != float64(int64(v)) {
+				return []string{path + " must be an integer"}
+			}
+		default:
+			return []string{path + " must be an integer"}
+		}
+	case reflect.String:
+		switch value.(type) {
+		case map[string]any, []any:
+			return []string{path + " must be a string"}
+		}
+	}
+	return nil
+}
+
+// configFields maps the yaml names of the exported fields of t to their types.
+func configFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
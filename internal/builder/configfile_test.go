@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindConfigFilePrefersYAML(t *testing.T) {
+	dir := t.TempDir()
+	if path, err := FindConfigFile(dir); err != nil || path != "" {
+		t.Fatalf("expected no config file, got %q (err=%v)", path, err)
+	}
+
+	for _, name := range []string{"docbuilder.json", "docbuilder.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	path, err := FindConfigFile(dir)
+	if err != nil {
+		t.Fatalf("FindConfigFile returned error: %v", err)
+	}
+	if filepath.Base(path) != "docbuilder.yaml" {
+		t.Fatalf("expected docbuilder.yaml to be preferred, got %q", path)
+	}
+}
+
+func TestLoadConfigFileAppliesValues(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "docbuilder.yaml")
+	content := "prefix: API_\nsearch: ../src\nengine: vitepress\nverbose: true\n"
+	if err := os.WriteFile(yamlPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	base := Config{Prefix: "DOC_", DocDir: ".", TempDirName: "temp"}
+	cfg, err := LoadConfigFile(yamlPath, base)
+	if err != nil {
+		t.Fatalf("LoadConfigFile returned error: %v", err)
+	}
+	if cfg.Prefix != "API_" || !cfg.Verbose {
+		t.Fatalf("expected file values to be applied, got %+v", cfg)
+	}
+	if cfg.TempDirName != "temp" || cfg.DocDir != "." {
+		t.Fatalf("expected unset keys to keep base values, got %+v", cfg)
+	}
+	if cfg.SearchPath != filepath.Join(dir, "..", "src") {
+		t.Fatalf("expected search path relative to config file, got %q", cfg.SearchPath)
+	}
+
+	jsonPath := filepath.Join(dir, "docbuilder.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"temp-dir": "build"}`), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	cfg, err = LoadConfigFile(jsonPath, base)
+	if err != nil {
+		t.Fatalf("LoadConfigFile returned error for JSON: %v", err)
+	}
+	if cfg.TempDirName != "build" {
+		t.Fatalf("expected JSON temp-dir to be applied, got %q", cfg.TempDirName)
+	}
+}
+
+func TestLoadConfigFileRejectsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docbuilder.yaml")
+	if err := os.WriteFile(path, []byte("prefix: DOC_\nsearch_path: ..\ntheme: dark\n"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	_, err := LoadConfigFile(path, Config{})
+	if err == nil {
+		t.Fatalf("expected unknown keys to be rejected")
+	}
+	if !strings.Contains(err.Error(), "search_path, theme") {
+		t.Fatalf("expected error to list unknown keys, got %v", err)
+	}
+}
+
+func TestLoadConfigFileReportsMistypedValues(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docbuilder.yaml")
+	content := "verbose: \"yes\"\napi-reference: true\ncategory-inference:\n  strip: src\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	_, err := LoadConfigFile(path, Config{})
+	if err == nil {
+		t.Fatalf("expected mistyped values to be rejected")
+	}
+	want := "api-reference must be a mapping, category-inference.strip must be a list, verbose must be true or false"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected error to name every mistyped key, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docsifyTitlePlaceholder is replaced with the site title in a custom
+// index.html, which is otherwise copied as is.
+const docsifyTitlePlaceholder = "{{ .Title }}"
+
+func init() {
+	registerEngine("docsify", func(cfg Config) Engine {
+		return &docsifyEngine{cfg: cfg}
+	})
+}
+
+// docsifyEngine copies the pages next to a generated _sidebar.md and an
+// index.html. Docsify renders markdown in the browser, so there is no build
+// step and the workspace is published as is.
+type docsifyEngine struct {
+	cfg Config
+}
+
+func (e *docsifyEngine) Name() string {
+	return "docsify"
+}
+
+func (e *docsifyEngine) Prepare(env *environment) error {
+	env.contentDir = filepath.Join(env.tempDir, "site")
+	return nil
+}
+
+func (e *docsifyEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Generating Docsify _sidebar.md")
+	}
+
+	lines := []string{}
+	if _, err := os.Stat(filepath.Join(env.contentDir, "index.md")); err == nil {
+		lines = append(lines, "- [Home](/)")
+	}
+	for _, sec := range sections {
+		lines = appendDocsifySection(lines, sec, 0)
+	}
+	lines = append(lines, "")
+
+	target := filepath.Join(env.contentDir, "_sidebar.md")
+	if err := os.MkdirAll(env.contentDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", env.contentDir, err)
+	}
+	if err := os.WriteFile(target, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func (e *docsifyEngine) Build(ctx context.Context, env environment) error {
+	if e.cfg.Verbose {
+		fmt.Println("[5/7] Preparing Docsify index.html (no build step required)")
+	}
+
+	title := deriveSiteTitle(env)
+	if err := renameDocsifyIndexes(env); err != nil {
+		return err
+	}
+
+	customIndex := filepath.Join(env.docDir, "index.html")
+	//nolint:gosec // file path is validated and safe
+	index, err := os.ReadFile(customIndex)
+	switch {
+	case err == nil:
+		index = []byte(strings.ReplaceAll(string(index), docsifyTitlePlaceholder, html.EscapeString(title)))
+	case errors.Is(err, os.ErrNotExist):
+		if index, err = renderDocsifyIndex(title); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("failed to read %s: %w", customIndex, err)
+	}
+
+	target := filepath.Join(env.contentDir, "index.html")
+	if err := os.WriteFile(target, index, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	nojekyll := filepath.Join(env.contentDir, ".nojekyll")
+	if err := os.WriteFile(nojekyll, nil, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", nojekyll, err)
+	}
+	return nil
+}
+
+func (e *docsifyEngine) Output(env environment) (string, string) {
+	return env.contentDir, filepath.Join(env.docDir, "dist")
+}
+
+func (e *docsifyEngine) PageURL(rec menuRecord) string {
+	return "#" + sitePath(rec, "", true)
+}
+
+// formatLink emits root relative routes, since Docsify resolves relative
+// links against the site root unless relativePath is enabled.
+func (e *docsifyEngine) formatLink(_, to page, anchor string) string {
+	link := sitePath(to.record, "", true)
+	if anchor != "" {
+		link += "?id=" + anchor
+	}
+	return link
+}
+
+func (e *docsifyEngine) formatAsset(_ page, rel string) string {
+	return "/" + rel
+}
+
+// renderDocsifyIndex renders the embedded index.html template.
+func renderDocsifyIndex(title string) ([]byte, error) {
+	source, err := embeddedTheme.ReadFile("theme/docsify.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded docsify template: %w", err)
+	}
+	tmpl, err := template.New("index.html").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded docsify template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Title string }{Title: title}); err != nil {
+		return nil, fmt.Errorf("failed to render embedded docsify template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func appendDocsifySection(lines []string, sec *section, depth int) []string {
+	indent := strings.Repeat("  ", depth)
+	label := escapeSummaryTitle(sec.Title)
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			label = fmt.Sprintf("[%s](/%s/)", label, item.CategoryPath)
+			break
+		}
+	}
+	lines = append(lines, indent+"- "+label)
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			continue
+		}
+		link := strings.Trim(item.CategoryPath+"/"+item.Slug, "/")
+		lines = append(lines, fmt.Sprintf("%s  - [%s](/%s)", indent, escapeSummaryTitle(item.Title), link))
+	}
+	for _, sub := range sec.Sections {
+		lines = appendDocsifySection(lines, sub, depth+1)
+	}
+	return lines
+}
+
+// renameDocsifyIndexes renames index.md pages to README.md, which Docsify
+// serves as the landing page of a directory.
+func renameDocsifyIndexes(env environment) error {
+	return filepath.WalkDir(env.contentDir, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != "index.md" {
+			return nil
+		}
+		target := filepath.Join(filepath.Dir(path), "README.md")
+		if err := os.Rename(path, target); err != nil {
+			return fmt.Errorf("failed to rename %s to %s: %w", path, target, err)
+		}
+		env.sources.rename(env, path, target)
+		return nil
+	})
+}
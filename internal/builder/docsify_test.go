@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocsifyEnginePublishesWithoutBuild(t *testing.T) {
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "docs")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Offline_Mode.md"), "---\ncategory: platform/mobile/features\ntitle: Offline Mode\n---\n# Offline Mode\n")
+	writeTestFile(t, filepath.Join(docDir, "guides", "index.md"), "# Guides Home\n")
+	writeTestFile(t, filepath.Join(docDir, "guides", "setup.md"), "# Setup\n")
+	writeTestFile(t, filepath.Join(docDir, "index.md"), "---\ntitle: Team Docs\n---\n# Welcome\n")
+	writeTestFile(t, filepath.Join(docDir, "index.html"), "<title>{{ .Title }}</title>\n<script>window.$docsify = { name: '{{ .Title }}', loadSidebar: true, vueComponents: { counter: { template: '<b>{{ count }}</b>' } } };</script>\n")
+
+	cfg := Config{Prefix: "DOC_", Engine: "docsify", SearchPath: searchDir, DocDir: docDir, TempDirName: "temp"}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	dist := filepath.Join(docDir, "dist")
+	sidebar, err := os.ReadFile(filepath.Join(dist, "_sidebar.md"))
+	if err != nil {
+		t.Fatalf("expected _sidebar.md: %v", err)
+	}
+	expected := strings.Join([]string{
+		"- [Home](/)",
+		"- [Guides](/guides/)",
+		"  - [Setup](/guides/setup)",
+		"- Platform",
+		"  - Mobile",
+		"    - Features",
+		"      - [Offline Mode](/platform/mobile/features/offline-mode)",
+		"",
+	}, "\n")
+	if string(sidebar) != expected {
+		t.Fatalf("unexpected sidebar:\n%s\nexpected:\n%s", sidebar, expected)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dist, "index.html"))
+	if err != nil {
+		t.Fatalf("expected index.html: %v", err)
+	}
+	for _, expect := range []string{"<title>Team Docs</title>", "name: 'Team Docs'", "<b>{{ count }}</b>"} {
+		if !strings.Contains(string(index), expect) {
+			t.Fatalf("expected %q in index.html, got %s", expect, index)
+		}
+	}
+
+	for _, rel := range []string{"README.md", filepath.Join("guides", "README.md"), filepath.Join("platform", "mobile", "features", "offline-mode.md")} {
+		if _, err := os.Stat(filepath.Join(dist, rel)); err != nil {
+			t.Fatalf("expected %s to be published: %v", rel, err)
+		}
+	}
+}
+
+func TestRenderDocsifyIndex(t *testing.T) {
+	index, err := renderDocsifyIndex("Ops & Docs")
+	if err != nil {
+		t.Fatalf("renderDocsifyIndex returned error: %v", err)
+	}
+	if !strings.Contains(string(index), "<title>Ops &amp; Docs</title>") || !strings.Contains(string(index), `name: "Ops \u0026 Docs"`) {
+		t.Fatalf("expected escaped title in embedded template, got %s", index)
+	}
+}
@@ -0,0 +1,192 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docusaurusConfigNames lists the config files Docusaurus accepts, in order of preference.
+var docusaurusConfigNames = []string{"docusaurus.config.js", "docusaurus.config.ts", "docusaurus.config.mjs"}
+
+// docusaurusProjectDirs are copied from the documentation directory into the
+// workspace next to the generated docs/ tree.
+var docusaurusProjectDirs = []string{"src", "static"}
+
+func init() {
+	registerEngine("docusaurus", func(cfg Config) Engine {
+		return &docusaurusEngine{cfg: cfg}
+	})
+}
+
+// docusaurusEngine lays the pages out in docs/, emits sidebars.js together
+// with _category_.json metadata and builds the site with `npm run build`.
+type docusaurusEngine struct {
+	cfg        Config
+	configFile string
+}
+
+func (e *docusaurusEngine) Name() string {
+	return "docusaurus"
+}
+
+func (e *docusaurusEngine) Prepare(env *environment) error {
+	for _, name := range docusaurusConfigNames {
+		candidate := filepath.Join(env.docDir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			e.configFile = name
+			break
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to access %s: %w", candidate, err)
+		}
+	}
+	if e.configFile == "" {
+		return fmt.Errorf("expected file not found: %s", filepath.Join(env.docDir, docusaurusConfigNames[0]))
+	}
+	if err := requireFile(filepath.Join(env.docDir, "package.json")); err != nil {
+		return err
+	}
+
+	env.contentDir = filepath.Join(env.tempDir, "docs")
+	for _, name := range docusaurusProjectDirs {
+		env.reservedDirs = append(env.reservedDirs, filepath.Join(env.docDir, name))
+	}
+	return nil
+}
+
+func (e *docusaurusEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Generating Docusaurus sidebars.js and category metadata")
+	}
+
+	lines := []string{
+		"// Generated by doc-builder. Do not edit.",
+		"module.exports = {",
+		"  docs: [",
+	}
+	if _, err := os.Stat(filepath.Join(env.contentDir, "index.md")); err == nil {
+		lines = append(lines, "    'index',")
+	}
+	for i, sec := range sections {
+		lines = appendDocusaurusCategory(lines, sec, 2)
+		if err := writeDocusaurusCategory(env, sec, i+1); err != nil {
+			return err
+		}
+	}
+	lines = append(lines, "  ],", "};", "")
+
+	target := filepath.Join(env.tempDir, "sidebars.js")
+	if err := os.WriteFile(target, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func (e *docusaurusEngine) Build(ctx context.Context, env environment) error {
+	if err := copyFile(filepath.Join(env.docDir, e.configFile), filepath.Join(env.tempDir, e.configFile)); err != nil {
+		return err
+	}
+	for _, name := range docusaurusProjectDirs {
+		src := filepath.Join(env.docDir, name)
+		if info, err := os.Stat(src); err == nil && info.IsDir() {
+			if err := copyDirectory(src, filepath.Join(env.tempDir, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	pm, err := installDependencies(ctx, e.cfg, env)
+	if err != nil {
+		return err
+	}
+	return runPackageScript(ctx, e.cfg, env, pm, "build")
+}
+
+func (e *docusaurusEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, "build"), filepath.Join(env.docDir, "build")
+}
+
+func (e *docusaurusEngine) PageURL(rec menuRecord) string {
+	return "/docs" + sitePath(rec, "", true)
+}
+
+// appendDocusaurusCategory renders sec as a sidebar category. A category index
+// page becomes the category link instead of a separate entry.
+func appendDocusaurusCategory(lines []string, sec *section, depth int) []string {
+	indent := strings.Repeat("  ", depth)
+	lines = append(lines, indent+"{")
+	lines = append(lines, indent+"  type: 'category',")
+	lines = append(lines, fmt.Sprintf("%s  label: %s,", indent, jsString(sec.Title)))
+	lines = append(lines, fmt.Sprintf("%s  collapsed: %t,", indent, sec.Collapsed))
+	if sec.Icon != "" {
+		lines = append(lines, fmt.Sprintf("%s  customProps: { icon: %s },", indent, jsString(sec.Icon)))
+	}
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			lines = append(lines, fmt.Sprintf("%s  link: { type: 'doc', id: %s },", indent, jsString(docusaurusID(item))))
+			break
+		}
+	}
+	lines = append(lines, indent+"  items: [")
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s    %s,", indent, jsString(docusaurusID(item))))
+	}
+	for _, sub := range sec.Sections {
+		lines = appendDocusaurusCategory(lines, sub, depth+2)
+	}
+	lines = append(lines, indent+"  ],")
+	lines = append(lines, indent+"},")
+	return lines
+}
+
+// writeDocusaurusCategory emits _category_.json files so autogenerated sidebars
+// and category index pages pick up the same labels and ordering.
+func writeDocusaurusCategory(env environment, sec *section, position int) error {
+	if sec.Key != "" {
+		meta := map[string]any{
+			"label":     sec.Title,
+			"position":  position,
+			"collapsed": sec.Collapsed,
+		}
+		if sec.Icon != "" {
+			meta["customProps"] = map[string]any{"icon": sec.Icon}
+		}
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode category metadata for %s: %w", sec.Key, err)
+		}
+		target := filepath.Join(env.contentDir, filepath.FromSlash(sec.Key), "_category_.json")
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+
+	for i, sub := range sec.Sections {
+		if err := writeDocusaurusCategory(env, sub, len(sec.Items)+i+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func docusaurusID(item menuRecord) string {
+	return strings.Trim(item.CategoryPath+"/"+item.Slug, "/")
+}
+
+func jsString(value string) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "''"
+	}
+	return string(data)
+}
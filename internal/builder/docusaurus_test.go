@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocusaurusEngineRendersSidebarsAndCategories(t *testing.T) {
+	docDir := t.TempDir()
+	writeTestFile(t, filepath.Join(docDir, "docusaurus.config.js"), "module.exports = {};\n")
+	writeTestFile(t, filepath.Join(docDir, "package.json"), "{}")
+
+	engine := &docusaurusEngine{}
+	env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")}
+	if err := engine.Prepare(&env); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if env.contentDir != filepath.Join(env.tempDir, "docs") {
+		t.Fatalf("expected pages under docs/, got %q", env.contentDir)
+	}
+
+	records := []menuRecord{
+		{CategoryPath: "guides", Slug: "index", Title: "Guides Home"},
+		{CategoryPath: "guides", Slug: "setup", Title: "Setup"},
+		{CategoryPath: "guides/advanced", Slug: "tuning", Title: "Tuning"},
+		{CategoryPath: "platform", Slug: "tour", Title: "Platform's Tour"},
+	}
+	if err := engine.RenderNavigation(env, buildSections(records, nil)); err != nil {
+		t.Fatalf("RenderNavigation returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.tempDir, "sidebars.js"))
+	if err != nil {
+		t.Fatalf("expected sidebars.js: %v", err)
+	}
+	sidebar := string(data)
+	for _, expect := range []string{
+		"label: \"Guides\",",
+		"link: { type: 'doc', id: \"guides/index\" },",
+		"\"guides/setup\",",
+		"label: \"Advanced\",",
+		"\"guides/advanced/tuning\",",
+		"\"platform/tour\",",
+	} {
+		if !strings.Contains(sidebar, expect) {
+			t.Fatalf("expected sidebars.js to contain %q, got:\n%s", expect, sidebar)
+		}
+	}
+	if strings.Contains(sidebar, "    \"guides/index\",") {
+		t.Fatalf("expected category index to be used as link only, got:\n%s", sidebar)
+	}
+
+	var meta struct {
+		Label    string `json:"label"`
+		Position int    `json:"position"`
+	}
+	raw, err := os.ReadFile(filepath.Join(env.contentDir, "guides", "advanced", "_category_.json"))
+	if err != nil {
+		t.Fatalf("expected nested _category_.json: %v", err)
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		t.Fatalf("invalid _category_.json: %v", err)
+	}
+	if meta.Label != "Advanced" || meta.Position != 3 {
+		t.Fatalf("unexpected category metadata %+v", meta)
+	}
+	if _, err := os.Stat(filepath.Join(env.contentDir, "platform", "_category_.json")); err != nil {
+		t.Fatalf("expected top-level _category_.json: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Engine turns the collected markdown workspace into a static site. Each
+// implementation owns the layout it expects inside the documentation
+// directory, the navigation format and the build tooling it shells out to.
+type Engine interface {
+	// Name returns the identifier used with --engine.
+	Name() string
+	// Prepare validates the documentation workspace and sets the directory
+	// that collected pages are written to (env.contentDir).
+	Prepare(env *environment) error
+	// RenderNavigation writes the engine specific navigation for sections.
+	RenderNavigation(env environment, sections []*section) error
+	// Build produces the static site inside the temporary workspace.
+	Build(ctx context.Context, env environment) error
+	// Output returns the directory produced by Build and the location it is
+	// published to.
+	Output(env environment) (string, string)
+	// PageURL returns the URL path rec is served at in the built site.
+	PageURL(rec menuRecord) string
+}
+
+type engineFactory func(cfg Config) Engine
+
+var engineRegistry = map[string]engineFactory{}
+
+func registerEngine(name string, factory engineFactory) {
+	key := strings.ToLower(name)
+	if _, exists := engineRegistry[key]; exists {
+		panic(fmt.Sprintf("engine %q registered twice", name))
+	}
+	engineRegistry[key] = factory
+}
+
+// EngineNames returns the names of all registered engines in alphabetical order.
+func EngineNames() []string {
+	names := make([]string, 0, len(engineRegistry))
+	for name := range engineRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newEngine(cfg Config) (Engine, error) {
+	factory, ok := engineRegistry[strings.ToLower(cfg.Engine)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported engine '%s': available engines are %s", cfg.Engine, strings.Join(EngineNames(), ", "))
+	}
+	return factory(cfg), nil
+}
@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewEngineLooksUpRegistry(t *testing.T) {
+	engine, err := newEngine(Config{Engine: "VitePress"})
+	if err != nil {
+		t.Fatalf("expected vitepress engine, got error: %v", err)
+	}
+	if engine.Name() != "vitepress" {
+		t.Fatalf("unexpected engine name %q", engine.Name())
+	}
+
+	_, err = newEngine(Config{Engine: "unknown"})
+	if err == nil || !strings.Contains(err.Error(), "vitepress") {
+		t.Fatalf("expected error listing available engines, got %v", err)
+	}
+}
+
+func TestVitepressEngineRendersSidebar(t *testing.T) {
+	docDir := t.TempDir()
+	base := "export default {\n  sidebar: [\n    " + vitepressPlaceholder + "\n  ]\n}\n"
+	writeTestFile(t, filepath.Join(docDir, ".vitepress", "base.config.js"), base)
+	writeTestFile(t, filepath.Join(docDir, "package.json"), "{}")
+
+	engine := &vitepressEngine{}
+	env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")}
+	if err := engine.Prepare(&env); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if env.contentDir != env.tempDir {
+		t.Fatalf("expected pages to be written to the temp root, got %q", env.contentDir)
+	}
+
+	sections := buildSections([]menuRecord{{CategoryPath: "guides", Slug: "intro", Title: "Intro"}}, nil)
+	if err := engine.RenderNavigation(env, sections); err != nil {
+		t.Fatalf("RenderNavigation returned error: %v", err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(env.tempDir, ".vitepress", "config.js"),
+		filepath.Join(docDir, ".vitepress", "config.js"),
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", path, err)
+		}
+		if !strings.Contains(string(data), "link: '/guides/intro'") || strings.Contains(string(data), vitepressPlaceholder) {
+			t.Fatalf("expected placeholder to be replaced in %s, got %s", path, data)
+		}
+	}
+
+	src, dst := engine.Output(env)
+	if src != filepath.Join(env.tempDir, ".vitepress", "dist") || dst != filepath.Join(docDir, ".vitepress", "dist") {
+		t.Fatalf("unexpected output locations %q -> %q", src, dst)
+	}
+}
+
+func TestCollectExistingDocsSkipsVitepressDirectories(t *testing.T) {
+	docDir := t.TempDir()
+	writeTestFile(t, filepath.Join(docDir, ".vitepress", "base.config.js"), "export default {}\n")
+	writeTestFile(t, filepath.Join(docDir, ".vitepress", "theme", "README.md"), "# Theme\n")
+	writeTestFile(t, filepath.Join(docDir, ".vitepress", "dist", "guides", "intro.md"), "# Published\n")
+	writeTestFile(t, filepath.Join(docDir, "package.json"), "{}")
+	writeTestFile(t, filepath.Join(docDir, "guides", "intro.md"), "# Intro\n")
+
+	b := &Builder{cfg: Config{TempDirName: "temp"}, engine: &vitepressEngine{}}
+	env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp"), sources: sourceMap{}}
+	if err := b.engine.Prepare(&env); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+
+	pages, err := b.collectExistingDocs(env)
+	if err != nil {
+		t.Fatalf("collectExistingDocs returned error: %v", err)
+	}
+	if len(pages) != 1 || pages[0].record.CategoryPath != "guides" {
+		t.Fatalf("expected engine owned directories to be skipped, got %+v", pages)
+	}
+}
+
+func TestVitepressEngineRequiresBaseConfig(t *testing.T) {
+	env := environment{docDir: t.TempDir()}
+	err := (&vitepressEngine{}).Prepare(&env)
+	if err == nil || !strings.Contains(err.Error(), "base.config.js") {
+		t.Fatalf("expected missing base config error, got %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
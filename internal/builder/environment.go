@@ -9,13 +9,15 @@ import (
 )
 
 type environment struct {
-	docDir       string
-	searchRoot   string
-	tempDir      string
-	baseConfig   string
-	outputConfig string
-	distSrc      string
-	distDst      string
+	docDir     string
+	searchRoot string
+	tempDir    string
+	contentDir string
+	// reservedDirs are engine owned directories inside docDir that must not
+	// be merged as existing documentation.
+	reservedDirs []string
+	// sources maps collected pages back to their original files.
+	sources sourceMap
 }
 
 func (b *Builder) validateConfig() error {
@@ -25,9 +27,6 @@ func (b *Builder) validateConfig() error {
 	if b.cfg.Engine == "" {
 		return errors.New("engine cannot be empty")
 	}
-	if !strings.EqualFold(b.cfg.Engine, "vitepress") {
-		return fmt.Errorf("unsupported engine '%s': only 'vitepress' is currently available", b.cfg.Engine)
-	}
 	if b.cfg.SearchPath == "" {
 		return errors.New("search path cannot be empty")
 	}
@@ -37,10 +36,19 @@ func (b *Builder) validateConfig() error {
 	if b.cfg.TempDirName == "" {
 		return errors.New("temporary directory name cannot be empty")
 	}
+	if err := b.cfg.CategoryInference.validate(); err != nil {
+		return err
+	}
+	if err := b.cfg.APIReference.validate(); err != nil {
+		return err
+	}
+	if !validCollisionPolicy(b.cfg.OnCollision) {
+		return fmt.Errorf("unsupported collision policy '%s': expected %s", b.cfg.OnCollision, strings.Join(collisionPolicies, ", "))
+	}
 	return nil
 }
 
-func (b *Builder) prepareEnvironment() (environment, error) {
+func (b *Builder) prepareEnvironment(engine Engine) (environment, error) {
 	docDir, err := filepath.Abs(b.cfg.DocDir)
 	if err != nil {
 		return environment{}, fmt.Errorf("failed to resolve documentation directory: %w", err)
@@ -64,36 +72,30 @@ func (b *Builder) prepareEnvironment() (environment, error) {
 		return environment{}, fmt.Errorf("failed to access search path: %w", err)
 	}
 
-	tempDir := filepath.Join(docDir, b.cfg.TempDirName)
-	baseConfig := filepath.Join(docDir, ".vitepress", "base.config.js")
-	outputConfig := filepath.Join(docDir, ".vitepress", "config.js")
-	distSrc := filepath.Join(tempDir, ".vitepress", "dist")
-	distDst := filepath.Join(docDir, ".vitepress", "dist")
-
-	if _, err := os.Stat(baseConfig); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return environment{}, fmt.Errorf("expected file not found: %s", baseConfig)
-		}
-		return environment{}, fmt.Errorf("failed to access base config: %w", err)
+	env := environment{
+		docDir:     docDir,
+		searchRoot: searchRoot,
+		tempDir:    filepath.Join(docDir, b.cfg.TempDirName),
+		sources:    sourceMap{},
+	}
+	if err := engine.Prepare(&env); err != nil {
+		return environment{}, err
+	}
+	if env.contentDir == "" {
+		env.contentDir = env.tempDir
 	}
+	return env, nil
+}
 
-	packageJSON := filepath.Join(docDir, "package.json")
-	if _, err := os.Stat(packageJSON); err != nil {
+// requireFile reports a descriptive error when an expected workspace file is missing.
+func requireFile(path string) error {
+	if _, err := os.Stat(path); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return environment{}, fmt.Errorf("expected file not found: %s", packageJSON)
+			return fmt.Errorf("expected file not found: %s", path)
 		}
-		return environment{}, fmt.Errorf("failed to access %s: %w", packageJSON, err)
+		return fmt.Errorf("failed to access %s: %w", path, err)
 	}
-
-	return environment{
-		docDir:       docDir,
-		searchRoot:   searchRoot,
-		tempDir:      tempDir,
-		baseConfig:   baseConfig,
-		outputConfig: outputConfig,
-		distSrc:      distSrc,
-		distDst:      distDst,
-	}, nil
+	return nil
 }
 
 func (b *Builder) prepareWorkspace(env environment) error {
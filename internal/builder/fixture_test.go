@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// runFixture is a search path and documentation directory for tests that run
+// the whole build, using the html engine unless cfg is changed.
+type runFixture struct {
+	t         *testing.T
+	searchDir string
+	docDir    string
+	cfg       Config
+}
+
+func newRunFixture(t *testing.T) *runFixture {
+	t.Helper()
+	root := t.TempDir()
+	f := &runFixture{t: t, searchDir: filepath.Join(root, "src"), docDir: filepath.Join(root, "docs")}
+	for _, dir := range []string{f.searchDir, f.docDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+	}
+	f.cfg = Config{Prefix: "DOC_", Engine: "html", SearchPath: f.searchDir, DocDir: f.docDir, TempDirName: "temp"}
+	return f
+}
+
+func (f *runFixture) source(rel, content string) {
+	f.t.Helper()
+	writeTestFile(f.t, filepath.Join(f.searchDir, filepath.FromSlash(rel)), content)
+}
+
+func (f *runFixture) doc(rel, content string) {
+	f.t.Helper()
+	writeTestFile(f.t, filepath.Join(f.docDir, filepath.FromSlash(rel)), content)
+}
+
+func (f *runFixture) run() {
+	f.t.Helper()
+	if err := New(f.cfg).Run(context.Background()); err != nil {
+		f.t.Fatalf("Run returned error: %v", err)
+	}
+}
+
+// output returns the content of a published file, rel being relative to dist.
+func (f *runFixture) output(rel string) string {
+	f.t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.docDir, "dist", filepath.FromSlash(rel)))
+	if err != nil {
+		f.t.Fatalf("expected %s to be published: %v", rel, err)
+	}
+	return string(data)
+}
+
+func (f *runFixture) published(rel string) bool {
+	_, err := os.Stat(filepath.Join(f.docDir, "dist", filepath.FromSlash(rel)))
+	return err == nil
+}
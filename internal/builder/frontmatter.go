@@ -0,0 +1,372 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Front matter formats, identified by their opening delimiter.
+const (
+	frontMatterYAML = "yaml"
+	frontMatterTOML = "toml"
+	frontMatterJSON = "json"
+)
+
+var (
+	yamlErrorLine = regexp.MustCompile(`line (\d+): (.*)`)
+	flatYAMLLine  = regexp.MustCompile(`^([\w-]+):(?:\s+(.*))?$`)
+)
+
+// frontMatter is the metadata block at the top of a markdown file. The known
+// fields are typed; Raw keeps every key as decoded so it can be passed through.
+type frontMatter struct {
+	Title    string
+	Category string
+	// Order positions the page among its siblings, nil when not set. The
+	// Hugo style weight key is accepted as well.
+	Order *int
+	Tags  []string
+	// Draft pages are only published with Config.IncludeDrafts.
+	Draft bool
+	// Hidden pages are published but left out of the navigation. Set by
+	// hidden: true or sidebar: false.
+	Hidden bool
+	// Audience lists the audiences a page is written for, empty for every
+	// audience. visibility is accepted as an alias.
+	Audience []string
+	// Slug replaces the slug derived from the file name.
+	Slug string
+	// Aliases are the old URL paths of the page, including the Jekyll style
+	// redirect_from key.
+	Aliases []string
+	Raw     map[string]any
+}
+
+// frontMatterBlock is the location of the front matter inside a file.
+type frontMatterBlock struct {
+	Format string
+	// Data is the content between the delimiters; for JSON it includes the
+	// braces.
+	Data []byte
+	// Line is the 1-based line of the file on which Data starts.
+	Line int
+	// End is the offset of the first byte after the block.
+	End int
+}
+
+// splitFrontMatter locates a leading YAML (---), TOML (+++) or JSON ({ ... })
+// front matter block. ok is false when content has none.
+func splitFrontMatter(content []byte) (frontMatterBlock, bool) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines) == 0 {
+		return frontMatterBlock{}, false
+	}
+
+	first := string(bytes.TrimSpace(lines[0]))
+	var format string
+	switch {
+	case first == "---":
+		format = frontMatterYAML
+	case first == "+++":
+		format = frontMatterTOML
+	case first == "{}":
+		return frontMatterBlock{Format: frontMatterJSON, Data: lines[0], Line: 1, End: len(lines[0])}, true
+	case strings.HasPrefix(first, `{"`) && json.Valid(lines[0]):
+		return frontMatterBlock{Format: frontMatterJSON, Data: lines[0], Line: 1, End: len(lines[0])}, true
+	case first == "{" || strings.HasPrefix(first, `{"`):
+		format = frontMatterJSON
+	default:
+		return frontMatterBlock{}, false
+	}
+
+	offset := len(lines[0])
+	for i := 1; i < len(lines); i++ {
+		line := string(bytes.TrimSpace(lines[i]))
+		end := offset + len(lines[i])
+		indented := len(lines[i]) > 0 && (lines[i][0] == ' ' || lines[i][0] == '\t')
+		switch format {
+		case frontMatterYAML:
+			if line == "---" || line == "..." {
+				return frontMatterBlock{Format: format, Data: content[len(lines[0]):offset], Line: 2, End: end}, true
+			}
+		case frontMatterTOML:
+			if line == "+++" {
+				return frontMatterBlock{Format: format, Data: content[len(lines[0]):offset], Line: 2, End: end}, true
+			}
+		case frontMatterJSON:
+			if line == "}" && !indented {
+				return frontMatterBlock{Format: format, Data: content[:end], Line: 1, End: end}, true
+			}
+		}
+		offset = end
+	}
+	return frontMatterBlock{}, false
+}
+
+// parseFrontMatter decodes the front matter of content. Errors name source and
+// the line of the offending value.
+func parseFrontMatter(source string, content []byte) (frontMatter, error) {
+	fm := frontMatter{Raw: map[string]any{}}
+	block, ok := splitFrontMatter(content)
+	if !ok {
+		return fm, nil
+	}
+
+	fail := func(line int, format string, args ...any) error {
+		return fmt.Errorf("%s:%d: invalid front matter: %s", source, line, fmt.Sprintf(format, args...))
+	}
+
+	switch block.Format {
+	case frontMatterYAML:
+		if err := yaml.Unmarshal(block.Data, &fm.Raw); err != nil {
+			if raw, ok := parseFlatYAML(block.Data); ok {
+				fm.Raw = raw
+				break
+			}
+			message := strings.TrimPrefix(err.Error(), "yaml: ")
+			if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+				line, _ := strconv.Atoi(match[1])
+				return fm, fail(block.Line+line-1, "%s", match[2])
+			}
+			return fm, fail(block.Line, "%s", message)
+		}
+	case frontMatterTOML:
+		if _, err := toml.Decode(string(block.Data), &fm.Raw); err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				return fm, fail(block.Line+parseErr.Position.Line-1, "%s", parseErr.Message)
+			}
+			return fm, fail(block.Line, "%v", err)
+		}
+	case frontMatterJSON:
+		if err := json.Unmarshal(block.Data, &fm.Raw); err != nil {
+			offset := int64(0)
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr):
+				offset = syntaxErr.Offset
+			case errors.As(err, &typeErr):
+				offset = typeErr.Offset
+			}
+			line := block.Line + bytes.Count(block.Data[:min(int(offset), len(block.Data))], []byte("\n"))
+			return fm, fail(line, "%v", err)
+		}
+	}
+	if fm.Raw == nil {
+		fm.Raw = map[string]any{}
+	}
+
+	lineOf := func(key string) int {
+		return frontMatterKeyLine(block, key)
+	}
+	var err error
+	if fm.Title, err = frontMatterString(fm.Raw, "title"); err != nil {
+		return fm, fail(lineOf("title"), "%v", err)
+	}
+	if fm.Category, err = frontMatterString(fm.Raw, "category"); err != nil {
+		return fm, fail(lineOf("category"), "%v", err)
+	}
+	if fm.Slug, err = frontMatterString(fm.Raw, "slug"); err != nil {
+		return fm, fail(lineOf("slug"), "%v", err)
+	}
+	if strings.ContainsAny(fm.Slug, `/\`) || fm.Slug == "." || fm.Slug == ".." {
+		return fm, fail(lineOf("slug"), "slug must be a single path segment")
+	}
+	if fm.Order, err = frontMatterInt(fm.Raw, "order"); err != nil {
+		return fm, fail(lineOf("order"), "%v", err)
+	}
+	if fm.Order == nil {
+		// Hugo style weight is accepted as an alias of order.
+		if fm.Order, err = frontMatterInt(fm.Raw, "weight"); err != nil {
+			return fm, fail(lineOf("weight"), "%v", err)
+		}
+	}
+	if fm.Tags, err = frontMatterStrings(fm.Raw, "tags"); err != nil {
+		return fm, fail(lineOf("tags"), "%v", err)
+	}
+	if fm.Aliases, err = frontMatterStrings(fm.Raw, "aliases"); err != nil {
+		return fm, fail(lineOf("aliases"), "%v", err)
+	}
+	redirects, err := frontMatterStrings(fm.Raw, "redirect_from")
+	if err != nil {
+		return fm, fail(lineOf("redirect_from"), "%v", err)
+	}
+	fm.Aliases = append(fm.Aliases, redirects...)
+	if fm.Draft, err = frontMatterBool(fm.Raw, "draft"); err != nil {
+		return fm, fail(lineOf("draft"), "%v", err)
+	}
+	if fm.Hidden, err = frontMatterBool(fm.Raw, "hidden"); err != nil {
+		return fm, fail(lineOf("hidden"), "%v", err)
+	}
+	if fm.Audience, err = frontMatterStrings(fm.Raw, "audience"); err != nil {
+		return fm, fail(lineOf("audience"), "%v", err)
+	}
+	if fm.Audience == nil {
+		if fm.Audience, err = frontMatterStrings(fm.Raw, "visibility"); err != nil {
+			return fm, fail(lineOf("visibility"), "%v", err)
+		}
+	}
+	if _, ok := frontMatterValue(fm.Raw, "sidebar"); ok {
+		sidebar, err := frontMatterBool(fm.Raw, "sidebar")
+		if err != nil {
+			return fm, fail(lineOf("sidebar"), "%v", err)
+		}
+		fm.Hidden = fm.Hidden || !sidebar
+	}
+	return fm, nil
+}
+
+// parseFlatYAML reads front matter that is not valid YAML but consists of
+// plain key: value lines, such as title: Foo: Bar, the way earlier versions
+// did. ok is false when any line has another shape.
+func parseFlatYAML(data []byte) (map[string]any, bool) {
+	raw := map[string]any{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		match := flatYAMLLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false
+		}
+		raw[match[1]] = strings.Trim(strings.TrimSpace(match[2]), `"'`)
+	}
+	return raw, true
+}
+
+// stripFrontMatter returns content without its leading front matter block.
+func stripFrontMatter(content []byte) []byte {
+	if block, ok := splitFrontMatter(content); ok {
+		return content[block.End:]
+	}
+	return content
+}
+
+// frontMatterValue looks key up case-insensitively, preferring an exact match.
+func frontMatterValue(raw map[string]any, key string) (any, bool) {
+	if value, ok := raw[key]; ok {
+		return value, true
+	}
+	keys := make([]string, 0, len(raw))
+	for candidate := range raw {
+		keys = append(keys, candidate)
+	}
+	sort.Strings(keys)
+	for _, candidate := range keys {
+		if strings.EqualFold(candidate, key) {
+			return raw[candidate], true
+		}
+	}
+	return nil, false
+}
+
+func frontMatterString(raw map[string]any, key string) (string, error) {
+	value, ok := frontMatterValue(raw, key)
+	if !ok || value == nil {
+		return "", nil
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), nil
+	case bool, int, int64, float64, uint64:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("%s must be a string", key)
+}
+
+func frontMatterInt(raw map[string]any, key string) (*int, error) {
+	value, ok := frontMatterValue(raw, key)
+	if !ok || value == nil {
+		return nil, nil
+	}
+	var number int
+	switch v := value.(type) {
+	case int:
+		number = v
+	case int64:
+		number = int(v)
+	case uint64:
+		number = int(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("%s must be an integer", key)
+		}
+		number = int(v)
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", key)
+		}
+		number = parsed
+	default:
+		return nil, fmt.Errorf("%s must be an integer", key)
+	}
+	return &number, nil
+}
+
+func frontMatterStrings(raw map[string]any, key string) ([]string, error) {
+	value, ok := frontMatterValue(raw, key)
+	if !ok || value == nil {
+		return nil, nil
+	}
+	switch v := value.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		return []string{strings.TrimSpace(v)}, nil
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case string, bool, int, int64, float64, uint64:
+				values = append(values, strings.TrimSpace(fmt.Sprint(item)))
+			default:
+				return nil, fmt.Errorf("%s must be a list of strings", key)
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("%s must be a string or a list of strings", key)
+}
+
+func frontMatterBool(raw map[string]any, key string) (bool, error) {
+	value, ok := frontMatterValue(raw, key)
+	if !ok || value == nil {
+		return false, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return false, fmt.Errorf("%s must be true or false", key)
+}
+
+// frontMatterKeyLine returns the file line on which key is defined, falling
+// back to the first line of the block.
+func frontMatterKeyLine(block frontMatterBlock, key string) int {
+	pattern := regexp.MustCompile(`(?i)^\s*["']?` + regexp.QuoteMeta(key) + `["']?\s*[:=]`)
+	for i, line := range strings.Split(string(block.Data), "\n") {
+		if pattern.MatchString(line) {
+			return block.Line + i
+		}
+	}
+	return block.Line
+}
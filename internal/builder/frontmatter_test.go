@@ -0,0 +1,185 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	content := []byte("---\nTitle: Getting Started\nCategory: guides/intro\n---\n# Body\n")
+	fm, err := parseFrontMatter("page.md", content)
+	if err != nil {
+		t.Fatalf("parseFrontMatter returned error: %v", err)
+	}
+
+	if got := fm.Title; got != "Getting Started" {
+		t.Fatalf("expected title to be 'Getting Started', got %q", got)
+	}
+	if got := fm.Category; got != "guides/intro" {
+		t.Fatalf("expected category to be 'guides/intro', got %q", got)
+	}
+}
+
+func TestParseFrontMatterWithoutBlock(t *testing.T) {
+	content := []byte("# Heading only\n")
+	fm, err := parseFrontMatter("page.md", content)
+	if err != nil {
+		t.Fatalf("parseFrontMatter returned error: %v", err)
+	}
+	if len(fm.Raw) != 0 || fm.Title != "" {
+		t.Fatalf("expected empty front matter, got %+v", fm)
+	}
+}
+
+func TestParseFrontMatterTypedFields(t *testing.T) {
+	formats := map[string]string{
+		"yaml": strings.Join([]string{
+			"---",
+			"title: 'Deploy: Production'",
+			"category: ops/deploy",
+			"order: 3",
+			"tags: [ci, release]",
+			"draft: true",
+			"slug: deploy",
+			"aliases:",
+			"  - /old/deploy",
+			"  - /legacy/deploy",
+			"extra:",
+			"  owner: platform",
+			"description: >",
+			"  Spans",
+			"  lines",
+			"---",
+			"# Body",
+		}, "\n"),
+		"toml": strings.Join([]string{
+			"+++",
+			`title = "Deploy: Production"`,
+			`category = "ops/deploy"`,
+			"order = 3",
+			`tags = ["ci", "release"]`,
+			"draft = true",
+			`slug = "deploy"`,
+			`aliases = ["/old/deploy", "/legacy/deploy"]`,
+			`description = "Spans lines"`,
+			"[extra]",
+			`owner = "platform"`,
+			"+++",
+			"# Body",
+		}, "\n"),
+		"json": strings.Join([]string{
+			"{",
+			`  "title": "Deploy: Production",`,
+			`  "category": "ops/deploy",`,
+			`  "order": 3,`,
+			`  "tags": ["ci", "release"],`,
+			`  "draft": true,`,
+			`  "slug": "deploy",`,
+			`  "aliases": ["/old/deploy", "/legacy/deploy"],`,
+			`  "description": "Spans lines",`,
+			`  "extra": {`,
+			`    "owner": "platform"`,
+			`  }`,
+			"}",
+			"# Body",
+		}, "\n"),
+	}
+
+	for format, content := range formats {
+		fm, err := parseFrontMatter("page.md", []byte(content))
+		if err != nil {
+			t.Fatalf("%s: parseFrontMatter returned error: %v", format, err)
+		}
+		if fm.Title != "Deploy: Production" || fm.Category != "ops/deploy" || fm.Slug != "deploy" || !fm.Draft {
+			t.Fatalf("%s: unexpected scalar fields %+v", format, fm)
+		}
+		if fm.Order == nil || *fm.Order != 3 {
+			t.Fatalf("%s: expected order 3, got %v", format, fm.Order)
+		}
+		if strings.Join(fm.Tags, ",") != "ci,release" || strings.Join(fm.Aliases, ",") != "/old/deploy,/legacy/deploy" {
+			t.Fatalf("%s: unexpected lists %v %v", format, fm.Tags, fm.Aliases)
+		}
+		extra, ok := fm.Raw["extra"].(map[string]any)
+		if !ok || extra["owner"] != "platform" {
+			t.Fatalf("%s: expected nested raw value, got %#v", format, fm.Raw["extra"])
+		}
+		if got := strings.TrimSpace(fm.Raw["description"].(string)); got != "Spans lines" {
+			t.Fatalf("%s: unexpected description %q", format, got)
+		}
+		if body := string(stripFrontMatter([]byte(content))); body != "# Body" {
+			t.Fatalf("%s: unexpected body %q", format, body)
+		}
+	}
+}
+
+func TestParseFrontMatterMergesRedirectFrom(t *testing.T) {
+	content := "---\naliases: /old/setup\nredirect_from:\n  - /setup.html\n---\n"
+	fm, err := parseFrontMatter("page.md", []byte(content))
+	if err != nil {
+		t.Fatalf("parseFrontMatter returned error: %v", err)
+	}
+	if strings.Join(fm.Aliases, ",") != "/old/setup,/setup.html" {
+		t.Fatalf("unexpected aliases %v", fm.Aliases)
+	}
+}
+
+func TestParseFrontMatterHiddenPages(t *testing.T) {
+	cases := map[string]bool{
+		"---\nhidden: true\n---\n":   true,
+		"---\nsidebar: false\n---\n": true,
+		"---\nsidebar: true\n---\n":  false,
+		"---\ntitle: Page\n---\n":    false,
+	}
+	for content, want := range cases {
+		fm, err := parseFrontMatter("page.md", []byte(content))
+		if err != nil {
+			t.Fatalf("parseFrontMatter(%q) returned error: %v", content, err)
+		}
+		if fm.Hidden != want {
+			t.Fatalf("parseFrontMatter(%q) hidden = %v, want %v", content, fm.Hidden, want)
+		}
+	}
+}
+
+func TestParseFrontMatterErrorsPointAtLine(t *testing.T) {
+	cases := map[string]string{
+		"---\ntitle: ok\ntags:\n  - a\n - b\n---\n":    "docs/page.md:4: invalid front matter: did not find expected key",
+		"---\ntitle: ok\norder: first\n---\n":          "docs/page.md:3: invalid front matter: order must be an integer",
+		"---\ntitle: ok\n\ndraft: maybe\n---\n":        "docs/page.md:4: invalid front matter: draft must be true or false",
+		"+++\ntitle = \"ok\"\norder = \n+++\n":         "docs/page.md:3",
+		"{\n  \"title\": \"ok\",\n  \"order\": ,\n}\n": "docs/page.md:3",
+		"---\ntitle:\n  nested: value\n---\n# Body\n":  "docs/page.md:2: invalid front matter: title must be a string",
+		"---\ntitle: ok\nslug: guides/setup\n---\n":    "docs/page.md:3: invalid front matter: slug must be a single path segment",
+	}
+	for content, want := range cases {
+		_, err := parseFrontMatter("docs/page.md", []byte(content))
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Fatalf("parseFrontMatter(%q) error = %v, want prefix %q", content, err, want)
+		}
+	}
+}
+
+func TestParseFrontMatterToleratesFlatYAML(t *testing.T) {
+	content := "---\ntitle: Deploy: Production\norder: 2\ndraft: 'false'\n---\n# Body\n"
+	fm, err := parseFrontMatter("page.md", []byte(content))
+	if err != nil {
+		t.Fatalf("parseFrontMatter returned error: %v", err)
+	}
+	if fm.Title != "Deploy: Production" || fm.Order == nil || *fm.Order != 2 || fm.Draft {
+		t.Fatalf("unexpected front matter %+v", fm)
+	}
+}
+
+func TestParseFrontMatterSingleLineJSON(t *testing.T) {
+	content := "{\"title\": \"Foo\", \"order\": 1}\n# Foo\n\nBody\n"
+	fm, err := parseFrontMatter("page.md", []byte(content))
+	if err != nil {
+		t.Fatalf("parseFrontMatter returned error: %v", err)
+	}
+	if fm.Title != "Foo" || fm.Order == nil || *fm.Order != 1 {
+		t.Fatalf("unexpected front matter %+v", fm)
+	}
+	if body := string(stripFrontMatter([]byte(content))); body != "# Foo\n\nBody\n" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
@@ -1,11 +1,14 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 func ensureTempGitignore(tempDir string) error {
@@ -25,10 +28,24 @@ func shouldSkipDirectory(name string) bool {
 	return false
 }
 
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
 func samePath(a, b string) bool {
 	return filepath.Clean(a) == filepath.Clean(b)
 }
 
+// isWithin reports whether target is dir itself or lies somewhere below it.
+func isWithin(target, dir string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 func copyFile(src, dst string) error {
 	//nolint:gosec // file paths are validated and safe
 	input, err := os.ReadFile(src)
@@ -61,10 +78,63 @@ func copyDirectory(src, dst string) error {
 			}
 			return nil
 		}
-		return copyFile(path, target)
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("failed to read link %s: %w", path, err)
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return fmt.Errorf("failed to create link %s: %w", target, err)
+			}
+			return nil
+		}
+		if err := copyFile(path, target); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		if info.Mode()&0o111 != 0 {
+			if err := os.Chmod(target, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to set permissions on %s: %w", target, err)
+			}
+		}
+		return nil
 	})
 }
 
 func escapeQuotes(value string) string {
 	return strings.ReplaceAll(value, "'", "\\'")
 }
+
+// runTool executes an external build tool inside the workspace, streaming its
+// output with workspace paths mapped back to the original sources. When the
+// tool fails the last lines of that output are included in the error.
+func runTool(ctx context.Context, env environment, name string, args ...string) error {
+	rewriter := newSourceRewriter(env)
+	var (
+		mu   sync.Mutex
+		tail []string
+	)
+	stdout := &mappedOutput{mu: &mu, out: os.Stdout, rewriter: rewriter, tail: &tail}
+	stderr := &mappedOutput{mu: &mu, out: os.Stderr, rewriter: rewriter, tail: &tail}
+
+	//nolint:gosec // build tools are configured by the user
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = env.tempDir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+
+	if runErr != nil {
+		command := strings.Join(append([]string{name}, args...), " ")
+		if len(tail) == 0 {
+			return fmt.Errorf("%s failed: %w", command, runErr)
+		}
+		return fmt.Errorf("%s failed: %w\n%s", command, runErr, strings.Join(tail, "\n"))
+	}
+	return nil
+}
@@ -76,3 +76,20 @@ func TestDirectoryHelpers(t *testing.T) {
 		t.Fatalf("expected samePath to normalize entries")
 	}
 }
+
+func TestIsWithin(t *testing.T) {
+	root := filepath.FromSlash("/repo/docs")
+	cases := map[string]bool{
+		root:                                   true,
+		filepath.Join(root, "guides", "a.md"):  true,
+		filepath.Join(root, "..notes", "x.md"): true,
+		filepath.FromSlash("/repo"):            false,
+		filepath.FromSlash("/repo/docs-old"):   false,
+		filepath.FromSlash("/repo/other/x.md"): false,
+	}
+	for target, want := range cases {
+		if got := isWithin(target, root); got != want {
+			t.Fatalf("isWithin(%q, %q) = %v, want %v", target, root, got, want)
+		}
+	}
+}
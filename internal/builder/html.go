@@ -0,0 +1,308 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+//go:embed theme/layout.html theme/style.css theme/docsify.html
+var embeddedTheme embed.FS
+
+func init() {
+	registerEngine("html", func(cfg Config) Engine {
+		return &htmlEngine{cfg: cfg}
+	})
+}
+
+// htmlEngine renders the collected markdown to static HTML in pure Go, so a
+// site can be produced without Node.js or any other toolchain.
+type htmlEngine struct {
+	cfg      Config
+	sections []*section
+}
+
+type htmlPage struct {
+	Title     string
+	SiteTitle string
+	Root      string
+	Sidebar   template.HTML
+	Content   template.HTML
+}
+
+func (e *htmlEngine) Name() string {
+	return "html"
+}
+
+func (e *htmlEngine) Prepare(env *environment) error {
+	env.contentDir = filepath.Join(env.tempDir, "docs")
+	return nil
+}
+
+func (e *htmlEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Preparing HTML sidebar")
+	}
+	e.sections = sections
+	return nil
+}
+
+func (e *htmlEngine) Build(ctx context.Context, env environment) error {
+	if e.cfg.Verbose {
+		fmt.Println("[5/7] Rendering static HTML")
+	}
+
+	layout, err := template.ParseFS(embeddedTheme, "theme/layout.html")
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML layout: %w", err)
+	}
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+	)
+
+	distDir, _ := e.Output(env)
+	siteTitle := deriveSiteTitle(env)
+
+	hasIndex := false
+	err = filepath.WalkDir(env.contentDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(env.contentDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if filepath.Ext(rel) != ".md" {
+			return copyFile(p, filepath.Join(distDir, filepath.FromSlash(rel)))
+		}
+		if rel == "index.md" {
+			hasIndex = true
+		}
+
+		//nolint:gosec // file path is validated and safe
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		pagePath := strings.TrimSuffix(rel, ".md") + ".html"
+		body, err := renderHTMLContent(markdown, stripFrontMatter(data), pagePath)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", p, err)
+		}
+		fm, err := parseFrontMatter(p, data)
+		if err != nil {
+			return err
+		}
+		slug := strings.TrimSuffix(path.Base(rel), ".md")
+		page := htmlPage{
+			Title:   deriveTitle(data, fm.Title, slug, ""),
+			Content: template.HTML(body), //nolint:gosec // markdown is authored by the documentation owners
+		}
+		return e.writePage(layout, distDir, pagePath, siteTitle, page)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !hasIndex {
+		page := htmlPage{
+			Title:   siteTitle,
+			Content: template.HTML("<h1>" + html.EscapeString(siteTitle) + "</h1>"), //nolint:gosec // title is escaped
+		}
+		if err := e.writePage(layout, distDir, "index.html", siteTitle, page); err != nil {
+			return err
+		}
+	}
+
+	style, err := embeddedTheme.ReadFile("theme/style.css")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded stylesheet: %w", err)
+	}
+	target := filepath.Join(distDir, "assets", "style.css")
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.WriteFile(target, style, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func (e *htmlEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, "dist"), filepath.Join(env.docDir, "dist")
+}
+
+func (e *htmlEngine) PageURL(rec menuRecord) string {
+	return sitePath(rec, ".html", false)
+}
+
+// deriveSiteTitle returns the title of the root index page, falling back to the
+// name of the documentation directory.
+func deriveSiteTitle(env environment) string {
+	title := formatTitle(filepath.Base(env.docDir))
+	indexPath := filepath.Join(env.contentDir, "index.md")
+	//nolint:gosec // file path is validated and safe
+	if data, err := os.ReadFile(indexPath); err == nil {
+		fm, _ := parseFrontMatter(indexPath, data)
+		title = deriveTitle(data, fm.Title, title, "")
+	}
+	return title
+}
+
+func (e *htmlEngine) writePage(layout *template.Template, distDir, pagePath, siteTitle string, page htmlPage) error {
+	page.SiteTitle = siteTitle
+	page.Root = strings.Repeat("../", strings.Count(pagePath, "/"))
+	page.Sidebar = template.HTML(renderHTMLSidebar(e.sections, pagePath)) //nolint:gosec // sidebar entries are escaped
+
+	var buf bytes.Buffer
+	if err := layout.Execute(&buf, page); err != nil {
+		return fmt.Errorf("failed to render %s: %w", pagePath, err)
+	}
+	target := filepath.Join(distDir, filepath.FromSlash(pagePath))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.WriteFile(target, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// renderHTMLContent converts markdown to HTML and rewrites site links so they
+// resolve relative to pagePath, which keeps the output browsable from disk.
+func renderHTMLContent(markdown goldmark.Markdown, source []byte, pagePath string) (string, error) {
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	err := ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			n.Destination = []byte(rewriteHTMLLink(string(n.Destination), pagePath))
+		case *ast.Image:
+			n.Destination = []byte(rewriteHTMLLink(string(n.Destination), pagePath))
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func rewriteHTMLLink(link, pagePath string) string {
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "//") || strings.Contains(link, ":") {
+		return link
+	}
+
+	target, suffix := link, ""
+	if idx := strings.IndexAny(target, "?#"); idx >= 0 {
+		target, suffix = target[:idx], target[idx:]
+	}
+
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+		switch {
+		case target == "" || strings.HasSuffix(target, "/"):
+			target += "index.html"
+		case path.Ext(target) == "":
+			target += ".html"
+		}
+		target = relativeLink(pagePath, target)
+	}
+	if path.Ext(target) == ".md" {
+		target = strings.TrimSuffix(target, ".md") + ".html"
+	}
+	return target + suffix
+}
+
+func relativeLink(fromPage, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(fromPage)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+func renderHTMLSidebar(sections []*section, pagePath string) string {
+	var b strings.Builder
+	b.WriteString("<ul>\n")
+	for _, sec := range sections {
+		writeHTMLSidebarSection(&b, sec, pagePath)
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+func writeHTMLSidebarSection(b *strings.Builder, sec *section, pagePath string) {
+	open := ""
+	if !sec.Collapsed || sectionContains(sec, pagePath) {
+		open = " open"
+	}
+	title := html.EscapeString(sec.Title)
+	if sec.Icon != "" {
+		title = fmt.Sprintf(`<span class="icon">%s</span> %s`, html.EscapeString(sec.Icon), title)
+	}
+	fmt.Fprintf(b, "<li><details%s><summary>%s</summary>\n<ul>\n", open, title)
+	for _, item := range sec.Items {
+		target := htmlPagePath(item)
+		class := ""
+		if target == pagePath {
+			class = ` class="active"`
+		}
+		fmt.Fprintf(b, "<li><a%s href=\"%s\">%s</a></li>\n", class, html.EscapeString(relativeLink(pagePath, target)), html.EscapeString(item.Title))
+	}
+	for _, sub := range sec.Sections {
+		writeHTMLSidebarSection(b, sub, pagePath)
+	}
+	b.WriteString("</ul>\n</details></li>\n")
+}
+
+func sectionContains(sec *section, pagePath string) bool {
+	for _, item := range sec.Items {
+		if htmlPagePath(item) == pagePath {
+			return true
+		}
+	}
+	for _, sub := range sec.Sections {
+		if sectionContains(sub, pagePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func htmlPagePath(item menuRecord) string {
+	return strings.Trim(item.CategoryPath+"/"+item.Slug+".html", "/")
+}
@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLEngineRendersSiteWithoutToolchain(t *testing.T) {
+	f := newRunFixture(t)
+	page := strings.Join([]string{
+		"---",
+		"category: architecture/components/core",
+		"title: Core Services",
+		"---",
+		"# Core Services",
+		"",
+		"See the [guides](/guides/setup) and [context](../overview/context.md#actors).",
+		"",
+		"| Service | SLA |",
+		"|---------|-----|",
+		"| Billing | 99.9% |",
+		"",
+		"```go",
+		"fmt.Println(\"<hi>\")",
+		"```",
+		"",
+	}, "\n")
+	f.source("DOC_Core_Services.md", page)
+	f.doc("guides/setup.md", "# Setup\n")
+	f.run()
+
+	html := f.output("architecture/components/core/core-services.html")
+	for _, expect := range []string{
+		"<title>Core Services | Docs</title>",
+		`<link rel="stylesheet" href="../../../assets/style.css">`,
+		`<h1 id="core-services">Core Services</h1>`,
+		`<a href="../../../guides/setup.html">guides</a>`,
+		`<a href="../overview/context.html#actors">context</a>`,
+		"<table>",
+		"<td>Billing</td>",
+		`<code class="language-go">`,
+		"&lt;hi&gt;",
+		`<a class="active" href="core-services.html">Core Services</a>`,
+		`<a href="../../../guides/setup.html">Setup</a>`,
+	} {
+		if !strings.Contains(html, expect) {
+			t.Fatalf("expected rendered page to contain %q, got:\n%s", expect, html)
+		}
+	}
+	if strings.Contains(html, "category: architecture") {
+		t.Fatalf("expected front matter to be stripped, got:\n%s", html)
+	}
+
+	for _, name := range []string{"index.html", "assets/style.css", "guides/setup.html"} {
+		if !f.published(name) {
+			t.Fatalf("expected %s in dist", name)
+		}
+	}
+}
+
+func TestRewriteHTMLLink(t *testing.T) {
+	cases := map[string]string{
+		"https://example.com/x": "https://example.com/x",
+		"#section":              "#section",
+		"/":                     "../index.html",
+		"/guides/":              "../guides/index.html",
+		"/guides/setup?x=1":     "../guides/setup.html?x=1",
+		"/img/logo.png":         "../img/logo.png",
+		"other.md#top":          "other.html#top",
+		"img/flow.png":          "img/flow.png",
+	}
+	for input, expected := range cases {
+		if got := rewriteHTMLLink(input, "platform/tour.html"); got != expected {
+			t.Fatalf("rewriteHTMLLink(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// hugoConfigNames lists the site configuration files Hugo accepts, in order of preference.
+var hugoConfigNames = []string{"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json", "config.toml", "config.yaml", "config.yml", "config.json"}
+
+// hugoProjectDirs are copied from the documentation directory into the
+// workspace next to the generated content/ tree.
+var hugoProjectDirs = []string{"archetypes", "assets", "data", "i18n", "layouts", "static", "themes"}
+
+func init() {
+	registerEngine("hugo", func(cfg Config) Engine {
+		return &hugoEngine{cfg: cfg}
+	})
+}
+
+// hugoEngine writes pages into content/<category>/ with _index.md section
+// files, injects title and weight front matter from the sidebar ordering and
+// runs hugo.
+type hugoEngine struct {
+	cfg        Config
+	configFile string
+}
+
+func (e *hugoEngine) Name() string {
+	return "hugo"
+}
+
+func (e *hugoEngine) Prepare(env *environment) error {
+	for _, name := range hugoConfigNames {
+		candidate := filepath.Join(env.docDir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			e.configFile = name
+			break
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to access %s: %w", candidate, err)
+		}
+	}
+	if e.configFile == "" {
+		return fmt.Errorf("expected file not found: %s", filepath.Join(env.docDir, hugoConfigNames[0]))
+	}
+
+	env.contentDir = filepath.Join(env.tempDir, "content")
+	for _, name := range hugoProjectDirs {
+		env.reservedDirs = append(env.reservedDirs, filepath.Join(env.docDir, name))
+	}
+	return nil
+}
+
+func (e *hugoEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Writing Hugo section files and page weights")
+	}
+
+	if err := promoteHugoIndex(env, "", nil); err != nil {
+		return err
+	}
+	for i, sec := range sections {
+		if err := e.writeSection(env, sec, i+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *hugoEngine) Build(ctx context.Context, env environment) error {
+	if err := copyFile(filepath.Join(env.docDir, e.configFile), filepath.Join(env.tempDir, e.configFile)); err != nil {
+		return err
+	}
+	for _, name := range hugoProjectDirs {
+		src := filepath.Join(env.docDir, name)
+		if info, err := os.Stat(src); err == nil && info.IsDir() {
+			if err := copyDirectory(src, filepath.Join(env.tempDir, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	binary := e.cfg.Hugo.Binary
+	if binary == "" {
+		binary = "hugo"
+	}
+	if e.cfg.Verbose {
+		fmt.Printf("[5/7] Running %s\n", binary)
+	}
+	return runTool(ctx, env, binary, "--destination", "public")
+}
+
+func (e *hugoEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, "public"), filepath.Join(env.docDir, "public")
+}
+
+func (e *hugoEngine) PageURL(rec menuRecord) string {
+	return sitePath(rec, "/", true)
+}
+
+// formatLink uses relref because Hugo does not resolve links to markdown files.
+// Index pages are referenced by the _index.md they are promoted to.
+func (e *hugoEngine) formatLink(_, to page, anchor string) string {
+	target := "/" + to.rel
+	if path.Base(target) == "index.md" {
+		target = path.Join(path.Dir(target), "_index.md")
+	}
+	if anchor != "" {
+		target += "#" + anchor
+	}
+	return fmt.Sprintf(`{{< relref %q >}}`, target)
+}
+
+// formatAsset returns a root relative path, as pages are served one directory
+// below the location of their markdown file.
+func (e *hugoEngine) formatAsset(_ page, rel string) string {
+	return "/" + rel
+}
+
+// writeSection creates the _index.md of sec (reusing a category index page when
+// one was collected) and stamps every page with its position in the sidebar.
+func (e *hugoEngine) writeSection(env environment, sec *section, weight int) error {
+	if sec.Key != "" {
+		fields := []frontMatterField{{"title", strconv.Quote(sec.Title)}, {"weight", strconv.Itoa(weight)}}
+		if err := promoteHugoIndex(env, sec.Key, fields); err != nil {
+			return err
+		}
+	}
+
+	position := 0
+	for _, item := range sec.Items {
+		if item.Slug == "index" && sec.Key != "" {
+			continue
+		}
+		position++
+		path := filepath.Join(env.contentDir, filepath.FromSlash(item.CategoryPath), item.Slug+".md")
+		fields := []frontMatterField{{"title", strconv.Quote(item.Title)}, {"weight", strconv.Itoa(position)}}
+		if err := updateFrontMatterFile(path, fields); err != nil {
+			return err
+		}
+	}
+	for _, sub := range sec.Sections {
+		position++
+		if err := e.writeSection(env, sub, position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promoteHugoIndex turns <dir>/index.md into the section file <dir>/_index.md,
+// creating an empty one when the category has no index page.
+func promoteHugoIndex(env environment, key string, fields []frontMatterField) error {
+	dir := filepath.Join(env.contentDir, filepath.FromSlash(key))
+	index := filepath.Join(dir, "index.md")
+	target := filepath.Join(dir, "_index.md")
+
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(index)
+	switch {
+	case err == nil:
+		if err := os.Remove(index); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", index, err)
+		}
+		env.sources.rename(env, index, target)
+	case errors.Is(err, os.ErrNotExist):
+		if key == "" {
+			return nil
+		}
+		data = nil
+	default:
+		return fmt.Errorf("failed to read %s: %w", index, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	if err := os.WriteFile(target, setFrontMatterFields(data, fields), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func updateFrontMatterFile(path string, fields []frontMatterField) error {
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, setFrontMatterFields(data, fields), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// frontMatterField is a key with an already encoded scalar value. Quoted
+// strings and numbers are valid in YAML, TOML and JSON alike.
+type frontMatterField struct {
+	Key   string
+	Value string
+}
+
+// setFrontMatterFields sets top-level keys in the front matter of content,
+// keeping its YAML, TOML or JSON format, and adds a YAML block when there is
+// none.
+func setFrontMatterFields(content []byte, fields []frontMatterField) []byte {
+	if len(fields) == 0 {
+		return content
+	}
+	if block, ok := splitFrontMatter(content); ok {
+		var updated []byte
+		var err error
+		switch block.Format {
+		case frontMatterTOML:
+			return setTOMLFrontMatterFields(content, block, fields)
+		case frontMatterJSON:
+			updated, err = setJSONFrontMatterFields(content, block, fields)
+		default:
+			updated, err = setYAMLFrontMatterFields(content, block, fields)
+		}
+		if err != nil {
+			return content
+		}
+		return updated
+	}
+
+	header := []string{"---"}
+	for _, field := range fields {
+		header = append(header, field.Key+": "+field.Value)
+	}
+	return append([]byte(strings.Join(append(header, "---"), "\n")+"\n"), content...)
+}
+
+// setYAMLFrontMatterFields sets fields in the decoded --- block and encodes it
+// again, so multi-line values keep their shape.
+func setYAMLFrontMatterFields(content []byte, block frontMatterBlock, fields []frontMatterField) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(block.Data, &doc); err != nil {
+		raw, ok := parseFlatYAML(block.Data)
+		if !ok {
+			return nil, err
+		}
+		var mapping yaml.Node
+		if err := mapping.Encode(raw); err != nil {
+			return nil, err
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&mapping}}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("front matter is not a mapping")
+	}
+
+	for _, field := range fields {
+		var value yaml.Node
+		if err := yaml.Unmarshal([]byte(field.Value), &value); err != nil {
+			return nil, err
+		}
+		replaced := false
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if strings.EqualFold(root.Content[i].Value, field.Key) {
+				root.Content[i].Value = field.Key
+				root.Content[i+1] = value.Content[0]
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.Key}, value.Content[0])
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return append([]byte("---\n"+buf.String()+"---\n"), content[block.End:]...), nil
+}
+
+// setTOMLFrontMatterFields replaces or adds top-level keys ahead of the first
+// table of a +++ block.
+func setTOMLFrontMatterFields(content []byte, block frontMatterBlock, fields []frontMatterField) []byte {
+	header := strings.Split(strings.TrimSuffix(string(block.Data), "\n"), "\n")
+	if len(block.Data) == 0 {
+		header = nil
+	}
+	tables := len(header)
+	for i, line := range header {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			tables = i
+			break
+		}
+	}
+
+	var added []string
+	for _, field := range fields {
+		replaced := false
+		for i := 0; i < tables; i++ {
+			key, _, found := strings.Cut(header[i], "=")
+			if found && strings.EqualFold(strings.Trim(strings.TrimSpace(key), `"`), field.Key) {
+				header[i] = field.Key + " = " + field.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			added = append(added, field.Key+" = "+field.Value)
+		}
+	}
+
+	result := append([]string{"+++"}, header[:tables]...)
+	result = append(result, added...)
+	result = append(result, header[tables:]...)
+	result = append(result, "+++")
+	return append([]byte(strings.Join(result, "\n")+"\n"), content[block.End:]...)
+}
+
+// setJSONFrontMatterFields re-encodes a JSON front matter object with fields set.
+func setJSONFrontMatterFields(content []byte, block frontMatterBlock, fields []frontMatterField) ([]byte, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(block.Data, &values); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		for key := range values {
+			if strings.EqualFold(key, field.Key) {
+				delete(values, key)
+			}
+		}
+		values[field.Key] = json.RawMessage(field.Value)
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(append(data, '\n'), content[block.End:]...), nil
+}
@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSetFrontMatterFields(t *testing.T) {
+	fields := []frontMatterField{{"title", `"Setup"`}, {"weight", "2"}}
+
+	withBlock := []byte("---\nTitle: Old\ncategory: guides\n---\n# Body\n")
+	expected := "---\ntitle: \"Setup\"\ncategory: guides\nweight: 2\n---\n# Body\n"
+	if got := string(setFrontMatterFields(withBlock, fields)); got != expected {
+		t.Fatalf("unexpected front matter update:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	withoutBlock := []byte("# Body\n")
+	expected = "---\ntitle: \"Setup\"\nweight: 2\n---\n# Body\n"
+	if got := string(setFrontMatterFields(withoutBlock, fields)); got != expected {
+		t.Fatalf("unexpected front matter creation:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	if got := string(setFrontMatterFields(nil, fields)); got != "---\ntitle: \"Setup\"\nweight: 2\n---\n" {
+		t.Fatalf("unexpected front matter for empty content: %q", got)
+	}
+
+	multiLine := []byte("---\ndescription: |\n  First line\n  title: not a key\ntags:\n  - a\n  - b\n...\n# Body\n")
+	expected = "---\ndescription: |\n  First line\n  title: not a key\ntags:\n  - a\n  - b\ntitle: \"Setup\"\nweight: 2\n---\n# Body\n"
+	if got := string(setFrontMatterFields(multiLine, fields)); got != expected {
+		t.Fatalf("unexpected multi-line front matter update:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	flat := []byte("---\ntitle: Deploy: Production\n---\n# Body\n")
+	expected = "---\ntitle: \"Setup\"\nweight: 2\n---\n# Body\n"
+	if got := string(setFrontMatterFields(flat, fields)); got != expected {
+		t.Fatalf("unexpected flat front matter update:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	withTOML := []byte("+++\ntitle = \"Old\"\n[extra]\nowner = \"ops\"\n+++\n# Body\n")
+	expected = "+++\ntitle = \"Setup\"\nweight = 2\n[extra]\nowner = \"ops\"\n+++\n# Body\n"
+	if got := string(setFrontMatterFields(withTOML, fields)); got != expected {
+		t.Fatalf("unexpected TOML front matter update:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	withJSON := []byte("{\n  \"Title\": \"Old\"\n}\n# Body\n")
+	expected = "{\n  \"title\": \"Setup\",\n  \"weight\": 2\n}\n# Body\n"
+	if got := string(setFrontMatterFields(withJSON, fields)); got != expected {
+		t.Fatalf("unexpected JSON front matter update:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestHugoEngineBuildsWithStub(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub executable relies on a POSIX shell")
+	}
+
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "site")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Zeta.md"), "---\ncategory: guides\n---\n# Zeta Guide\n")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Core.md"), "---\ncategory: guides/advanced/core\ntitle: Core\n---\n# Core\n")
+	writeTestFile(t, filepath.Join(docDir, "guides", "index.md"), "---\ntitle: Guides Home\n---\n# Guides Home\n")
+	writeTestFile(t, filepath.Join(docDir, "guides", "alpha.md"), "# Alpha\n")
+	writeTestFile(t, filepath.Join(docDir, "index.md"), "# Home\n")
+	writeTestFile(t, filepath.Join(docDir, "hugo.toml"), "title = \"Docs\"\n")
+	writeTestFile(t, filepath.Join(docDir, "layouts", "_default", "single.html"), "{{ .Content }}\n")
+
+	stub := filepath.Join(root, "hugo-stub")
+	writeTestFile(t, stub, "#!/bin/sh\nset -e\ntest \"$1\" = --destination\ntest -f hugo.toml\nmkdir -p \"$2\"\ncp -R content \"$2/content\"\n")
+	if err := os.Chmod(stub, 0o755); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+
+	cfg := Config{
+		Prefix:      "DOC_",
+		Engine:      "hugo",
+		SearchPath:  searchDir,
+		DocDir:      docDir,
+		TempDirName: "temp",
+		Hugo:        HugoConfig{Binary: stub},
+	}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	content := filepath.Join(docDir, "public", "content")
+	expectations := map[string][]string{
+		"_index.md":                      {"# Home"},
+		"guides/_index.md":               {"title: \"Guides\"", "weight: 1", "# Guides Home"},
+		"guides/alpha.md":                {"title: \"Alpha\"", "weight: 1"},
+		"guides/zeta.md":                 {"title: \"Zeta Guide\"", "weight: 2", "category: guides"},
+		"guides/advanced/_index.md":      {"title: \"Advanced\"", "weight: 3"},
+		"guides/advanced/core/_index.md": {"title: \"Core\"", "weight: 1"},
+		"guides/advanced/core/core.md":   {"title: \"Core\"", "weight: 1"},
+	}
+	for rel, checks := range expectations {
+		data, err := os.ReadFile(filepath.Join(content, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("expected %s in published output: %v", rel, err)
+		}
+		for _, expect := range checks {
+			if !strings.Contains(string(data), expect) {
+				t.Fatalf("expected %s to contain %q, got:\n%s", rel, expect, data)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(content, "guides", "index.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected guides/index.md to be promoted to _index.md, stat err=%v", err)
+	}
+}
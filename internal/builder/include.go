@@ -0,0 +1,147 @@
+package builder
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxIncludeDepth limits how deeply included files may include other files.
+const maxIncludeDepth = 10
+
+var includePattern = regexp.MustCompile(`^<!--\s*@include:\s*(.*?)\s*-->$`)
+
+// expandIncludes replaces every <!-- @include: path --> line of the collected
+// pages with the content of the named file, and every <!-- @snippet: path -->
+// line with a code block, resolving paths against the file that contains the
+// directive. Relative links of included files are rebased onto the including
+// page.
+func (b *Builder) expandIncludes(pages []page) error {
+	for i := range pages {
+		p := &pages[i]
+		data, err := includeFile(p.record.Source, p.data, []string{filepath.Clean(p.record.Source)})
+		if err != nil {
+			return err
+		}
+		p.data = data
+	}
+	return nil
+}
+
+// includeFile expands the include and snippet directives of content, the text
+// of source.
+// stack holds the chain of files being included, starting with the page.
+func includeFile(source string, content []byte, stack []string) ([]byte, error) {
+	header, body := "", string(content)
+	if block, ok := splitFrontMatter(content); ok {
+		header, body = body[:block.End], body[block.End:]
+	}
+	offset := strings.Count(header, "\n")
+
+	var out strings.Builder
+	out.WriteString(header)
+	fence := ""
+	for i, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			out.WriteString(line)
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			out.WriteString(line)
+			continue
+		}
+
+		lineNo := offset + i + 1
+		var included []byte
+		var err error
+		if match := includePattern.FindStringSubmatch(trimmed); match != nil {
+			included, err = loadInclude(source, lineNo, match[1], stack)
+		} else if match := snippetPattern.FindStringSubmatch(trimmed); match != nil {
+			included, err = loadSnippet(source, lineNo, match[1])
+		} else {
+			out.WriteString(line)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out.Write(included)
+		if strings.HasSuffix(line, "\n") && len(included) > 0 && !strings.HasSuffix(string(included), "\n") {
+			out.WriteString("\n")
+		}
+	}
+	return []byte(out.String()), nil
+}
+
+// loadInclude reads the file named by the directive on line of source, expands
+// its own directives and rebases its links onto the directory of source.
+func loadInclude(source string, line int, target string, stack []string) ([]byte, error) {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%s:%d: %s", source, line, fmt.Sprintf(format, args...))
+	}
+
+	target = strings.Trim(target, `"'`)
+	if target == "" {
+		return nil, fail("include names no file")
+	}
+	path := filepath.Clean(filepath.Join(filepath.Dir(source), filepath.FromSlash(target)))
+	if filepath.IsAbs(filepath.FromSlash(target)) {
+		path = filepath.Clean(filepath.FromSlash(target))
+	}
+
+	for i, entry := range stack {
+		if entry == path {
+			chain := make([]string, 0, len(stack)-i+1)
+			for _, item := range stack[i:] {
+				chain = append(chain, displayPath(item))
+			}
+			return nil, fail("include cycle: %s -> %s", strings.Join(chain, " -> "), displayPath(path))
+		}
+	}
+	if len(stack) > maxIncludeDepth {
+		return nil, fail("includes are nested deeper than %d levels", maxIncludeDepth)
+	}
+
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fail("failed to include %s: %v", target, err)
+	}
+
+	expanded, err := includeFile(path, data, append(stack, path))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(rebaseLinks(string(stripFrontMatter(expanded)), filepath.Dir(path), filepath.Dir(source))), nil
+}
+
+// rebaseLinks rewrites the relative links of content, written in dir, so they
+// keep pointing at the same files from target.
+func rebaseLinks(content, dir, target string) string {
+	if samePath(dir, target) {
+		return content
+	}
+	return mapMarkdownLinks(content, func(link string, _ int) string {
+		linked, suffix, ok := splitRelativeLink(link)
+		if !ok {
+			return link
+		}
+		rel, err := filepath.Rel(target, filepath.Join(dir, filepath.FromSlash(linked)))
+		if err != nil {
+			return link
+		}
+		rebased := (&url.URL{Path: filepath.ToSlash(rel)}).EscapedPath() + suffix
+		if strings.HasPrefix(link, "<") {
+			return "<" + rebased + ">"
+		}
+		return rebased
+	})
+}
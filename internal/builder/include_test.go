@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandIncludes(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "pkg", "DOC_Setup.md")
+	writeTestFile(t, filepath.Join(root, "shared", "prereqs.md"), "---\ntitle: Partial\n---\n## Prerequisites\n\nSee [tools](tools.md) and ![diagram](img/flow.png).\n<!-- @include: support.md -->\n")
+	writeTestFile(t, filepath.Join(root, "shared", "support.md"), "Ask in [support](../pkg/DOC_Help.md#chat).")
+
+	content := "---\ntitle: Setup\n---\n# Setup\n<!-- @include: ../shared/prereqs.md -->\n```md\n<!-- @include: missing.md -->\n```\n"
+	pages := []page{{record: menuRecord{Source: source}, data: []byte(content)}}
+	if err := (&Builder{}).expandIncludes(pages); err != nil {
+		t.Fatalf("expandIncludes returned error: %v", err)
+	}
+
+	want := "---\ntitle: Setup\n---\n# Setup\n## Prerequisites\n\nSee [tools](../shared/tools.md) and ![diagram](../shared/img/flow.png).\n" +
+		"Ask in [support](DOC_Help.md#chat).\n```md\n<!-- @include: missing.md -->\n```\n"
+	if got := string(pages[0].data); got != want {
+		t.Fatalf("unexpected expansion:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExpandIncludesErrors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "a.md"), "intro\n<!-- @include: b.md -->\n")
+	writeTestFile(t, filepath.Join(root, "b.md"), "\n\n<!-- @include: a.md -->\n")
+	writeTestFile(t, filepath.Join(root, "self.md"), "<!-- @include: self.md -->\n")
+
+	cases := []struct {
+		content string
+		want    string
+	}{
+		{"<!-- @include: a.md -->\n", filepath.Join(root, "b.md") + ":3: include cycle: "},
+		{"<!-- @include: self.md -->\n", filepath.Join(root, "self.md") + ":1: include cycle: "},
+		{"# Page\n<!-- @include: missing.md -->\n", filepath.Join(root, "page.md") + ":2: failed to include missing.md"},
+		{"---\ntitle: x\n---\n<!-- @include: -->\n", filepath.Join(root, "page.md") + ":4: include names no file"},
+	}
+	for _, tc := range cases {
+		pages := []page{{record: menuRecord{Source: filepath.Join(root, "page.md")}, data: []byte(tc.content)}}
+		err := (&Builder{}).expandIncludes(pages)
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Fatalf("expandIncludes(%q) error = %v, want prefix %q", tc.content, err, tc.want)
+		}
+	}
+}
+
+func TestExpandIncludesLimitsDepth(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i <= maxIncludeDepth+1; i++ {
+		writeTestFile(t, filepath.Join(root, "part"+strings.Repeat("x", i)+".md"), "<!-- @include: part"+strings.Repeat("x", i+1)+".md -->\n")
+	}
+
+	pages := []page{{record: menuRecord{Source: filepath.Join(root, "page.md")}, data: []byte("<!-- @include: part.md -->\n")}}
+	err := (&Builder{}).expandIncludes(pages)
+	if err == nil || !strings.Contains(err.Error(), "nested deeper than 10 levels") {
+		t.Fatalf("expected depth error, got %v", err)
+	}
+}
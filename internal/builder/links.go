@@ -0,0 +1,221 @@
+package builder
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	inlineLinkPattern    = regexp.MustCompile(`\]\(\s*(<[^>]*>|[^)\s]+)`)
+	referenceLinkPattern = regexp.MustCompile(`^(\s{0,3}\[[^\]]+\]:\s*)(<[^>]*>|\S+)`)
+	htmlLinkPattern      = regexp.MustCompile(`(?i)\b(?:src|href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// linkFormatter is implemented by engines that cannot follow relative links
+// from a page and need links to collected pages and assets in another form.
+type linkFormatter interface {
+	formatLink(from, to page, anchor string) string
+	// formatAsset returns the reference to the asset stored at rel inside the
+	// content directory.
+	formatAsset(from page, rel string) string
+}
+
+// unresolvedLink is a link to a markdown file that was not collected.
+type unresolvedLink struct {
+	Source string
+	Line   int
+	Link   string
+}
+
+func (l unresolvedLink) String() string {
+	return fmt.Sprintf("%s:%d: %s", displayPath(l.Source), l.Line, l.Link)
+}
+
+// rewriteLinks points links between collected sources at the location the
+// target page has in the workspace. Links inside code are left alone and links
+// to markdown files that were not collected are returned.
+func (b *Builder) rewriteLinks(pages []page) []unresolvedLink {
+	bySource := make(map[string]int, len(pages))
+	for i, p := range pages {
+		bySource[filepath.Clean(p.record.Source)] = i
+	}
+	formatter, _ := b.engine.(linkFormatter)
+
+	var unresolved []unresolvedLink
+	for i := range pages {
+		from := pages[i]
+		resolve := func(link string, line int) string {
+			target, anchor, ok := splitMarkdownLink(link)
+			if !ok {
+				return link
+			}
+			resolved := filepath.Clean(filepath.Join(filepath.Dir(from.record.Source), filepath.FromSlash(target)))
+			idx, found := bySource[resolved]
+			if !found {
+				unresolved = append(unresolved, unresolvedLink{Source: from.record.Source, Line: line, Link: link})
+				return link
+			}
+			rewritten := relativePageLink(from.rel, pages[idx].rel, anchor)
+			if formatter != nil {
+				rewritten = formatter.formatLink(from, pages[idx], anchor)
+			}
+			if strings.HasPrefix(link, "<") {
+				return "<" + rewritten + ">"
+			}
+			return rewritten
+		}
+		pages[i].data = []byte(mapMarkdownLinks(string(from.data), resolve))
+	}
+
+	for _, link := range unresolved {
+		fmt.Fprintf(os.Stderr, "warning: unresolved link %s\n", link)
+	}
+	return unresolved
+}
+
+// splitMarkdownLink returns the unescaped path and anchor of a relative link to
+// a markdown file. ok is false for every other kind of link.
+func splitMarkdownLink(link string) (string, string, bool) {
+	target, suffix, ok := splitRelativeLink(link)
+	if !ok || path.Ext(target) != ".md" || strings.HasPrefix(suffix, "?") {
+		return "", "", false
+	}
+	return target, strings.TrimPrefix(suffix, "#"), true
+}
+
+// splitRelativeLink separates a relative link into its unescaped path and the
+// query or fragment suffix. ok is false for absolute, external and pure
+// fragment links.
+func splitRelativeLink(link string) (string, string, bool) {
+	link = strings.TrimSuffix(strings.TrimPrefix(link, "<"), ">")
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "/") || strings.Contains(link, ":") {
+		return "", "", false
+	}
+	target, suffix := link, ""
+	if idx := strings.IndexAny(target, "?#"); idx >= 0 {
+		target, suffix = target[:idx], target[idx:]
+	}
+	if target == "" {
+		return "", "", false
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	return target, suffix, true
+}
+
+func relativePageLink(fromRel, toRel, anchor string) string {
+	link := toRel
+	if rel, err := filepath.Rel(filepath.FromSlash(path.Dir(fromRel)), filepath.FromSlash(toRel)); err == nil {
+		link = filepath.ToSlash(rel)
+	}
+	if anchor != "" {
+		link += "#" + anchor
+	}
+	return link
+}
+
+// mapMarkdownLinks calls resolve for the destination of every inline link,
+// reference definition and HTML src or href attribute outside front matter,
+// fenced code blocks and code spans.
+func mapMarkdownLinks(content string, resolve func(link string, line int) string) string {
+	header := ""
+	if block, ok := splitFrontMatter([]byte(content)); ok {
+		header, content = content[:block.End], content[block.End:]
+	}
+	offset := strings.Count(header, "\n")
+
+	lines := strings.SplitAfter(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			continue
+		}
+
+		lineNo := offset + i + 1
+		if match := referenceLinkPattern.FindStringSubmatchIndex(line); match != nil {
+			lines[i] = line[:match[4]] + resolve(line[match[4]:match[5]], lineNo) + line[match[5]:]
+			continue
+		}
+		lines[i] = mapOutsideCodeSpans(line, func(text string) string {
+			text = inlineLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+				groups := inlineLinkPattern.FindStringSubmatchIndex(match)
+				return match[:groups[2]] + resolve(match[groups[2]:groups[3]], lineNo) + match[groups[3]:]
+			})
+			return htmlLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+				groups := htmlLinkPattern.FindStringSubmatchIndex(match)
+				start, end := groups[2], groups[3]
+				if start < 0 {
+					start, end = groups[4], groups[5]
+				}
+				return match[:start] + resolve(match[start:end], lineNo) + match[end:]
+			})
+		})
+	}
+	return header + strings.Join(lines, "")
+}
+
+// fenceMarker returns the backtick or tilde run opening a fenced code block.
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, char := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, char+char+char) {
+			return trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, char))]
+		}
+	}
+	return ""
+}
+
+// mapOutsideCodeSpans applies fn to the parts of line that are not inside
+// inline code spans.
+func mapOutsideCodeSpans(line string, fn func(string) string) string {
+	var out strings.Builder
+	for {
+		start := strings.Index(line, "`")
+		if start < 0 {
+			out.WriteString(fn(line))
+			return out.String()
+		}
+		run := len(line[start:]) - len(strings.TrimLeft(line[start:], "`"))
+		delimiter := line[start : start+run]
+		end := -1
+		for offset := start + run; offset < len(line); {
+			idx := strings.Index(line[offset:], delimiter)
+			if idx < 0 {
+				break
+			}
+			pos := offset + idx
+			after := pos + run
+			if after < len(line) && line[after] == '`' {
+				offset = after + len(line[after:]) - len(strings.TrimLeft(line[after:], "`"))
+				continue
+			}
+			end = after
+			break
+		}
+		if end < 0 {
+			out.WriteString(fn(line[:start+run]))
+			line = line[start+run:]
+			continue
+		}
+		out.WriteString(fn(line[:start]))
+		out.WriteString(line[start:end])
+		line = line[end:]
+	}
+}
@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func linkPages() []page {
+	return []page{
+		{
+			record: menuRecord{CategoryPath: "platform/api", Slug: "overview", Source: "/repo/api/DOC_Overview.md"},
+			rel:    "platform/api/overview.md",
+			data: []byte(strings.Join([]string{
+				"---",
+				"related: ../integrations/DOC_Webhooks.md",
+				"---",
+				"See [webhooks](../integrations/DOC_Webhooks.md#retries) and [me](DOC_Overview.md).",
+				"Code `[x](../integrations/DOC_Webhooks.md)` stays, as does [site](https://example.com/a.md).",
+				"Broken [link](missing.md) and an image ![logo](logo.png).",
+				"",
+				"```md",
+				"[fenced](../integrations/DOC_Webhooks.md)",
+				"```",
+				"",
+				"[ref]: <../integrations/DOC_Webhooks.md>",
+				"",
+			}, "\n")),
+		},
+		{
+			record: menuRecord{CategoryPath: "platform/integrations", Slug: "webhooks", Source: "/repo/integrations/DOC_Webhooks.md"},
+			rel:    "platform/integrations/webhooks.md",
+			data:   []byte("Back to [overview](../api/DOC_Overview.md)\n"),
+		},
+		{
+			record: menuRecord{CategoryPath: "guides", Slug: "index", Source: "/repo/docs/guides/index.md"},
+			rel:    "guides/index.md",
+			data:   []byte("[Webhooks](../../integrations/DOC_Webhooks.md)\n"),
+		},
+	}
+}
+
+func TestRewriteLinksUsesWorkspacePaths(t *testing.T) {
+	b := &Builder{engine: &htmlEngine{}}
+	pages := linkPages()
+	unresolved := b.rewriteLinks(pages)
+
+	overview := string(pages[0].data)
+	for _, expect := range []string{
+		"related: ../integrations/DOC_Webhooks.md",
+		"[webhooks](../integrations/webhooks.md#retries)",
+		"[me](overview.md)",
+		"`[x](../integrations/DOC_Webhooks.md)`",
+		"[site](https://example.com/a.md)",
+		"![logo](logo.png)",
+		"[fenced](../integrations/DOC_Webhooks.md)",
+		"[ref]: <../integrations/webhooks.md>",
+	} {
+		if !strings.Contains(overview, expect) {
+			t.Fatalf("expected %q in rewritten page:\n%s", expect, overview)
+		}
+	}
+	if got := string(pages[1].data); got != "Back to [overview](../api/overview.md)\n" {
+		t.Fatalf("unexpected rewritten page %q", got)
+	}
+	if got := string(pages[2].data); got != "[Webhooks](../platform/integrations/webhooks.md)\n" {
+		t.Fatalf("unexpected rewritten page %q", got)
+	}
+
+	if len(unresolved) != 1 || unresolved[0].Link != "missing.md" || unresolved[0].Line != 6 {
+		t.Fatalf("unexpected unresolved links: %+v", unresolved)
+	}
+}
+
+func TestRewriteLinksUsesEngineFormat(t *testing.T) {
+	pages := linkPages()
+	(&Builder{engine: &hugoEngine{}}).rewriteLinks(pages)
+	if !strings.Contains(string(pages[0].data), `[webhooks]({{< relref "/platform/integrations/webhooks.md#retries" >}})`) {
+		t.Fatalf("expected relref link, got:\n%s", pages[0].data)
+	}
+	if !strings.Contains(string(pages[1].data), `{{< relref "/platform/api/overview.md" >}}`) {
+		t.Fatalf("expected relref link, got:\n%s", pages[1].data)
+	}
+
+	pages = linkPages()
+	(&Builder{engine: &docsifyEngine{}}).rewriteLinks(pages)
+	if !strings.Contains(string(pages[0].data), "[webhooks](/platform/integrations/webhooks?id=retries)") {
+		t.Fatalf("expected docsify route, got:\n%s", pages[0].data)
+	}
+}
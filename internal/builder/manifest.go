@@ -0,0 +1,84 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manifestName is the file written into the workspace and the published
+// output describing every collected page.
+const manifestName = "manifest.json"
+
+type manifest struct {
+	Engine string         `json:"engine"`
+	Pages  []manifestPage `json:"pages"`
+}
+
+type manifestPage struct {
+	// Source is relative to the search path, using forward slashes.
+	Source   string `json:"source"`
+	Origin   string `json:"origin"`
+	Category string `json:"category"`
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Hash     string `json:"hash"`
+	// Hidden pages are published but not listed in the navigation.
+	Hidden      bool           `json:"hidden,omitempty"`
+	FrontMatter map[string]any `json:"front-matter,omitempty"`
+}
+
+func (b *Builder) writeManifest(env environment, pages []page) error {
+	doc := manifest{Engine: b.engine.Name(), Pages: make([]manifestPage, 0, len(pages))}
+	for _, p := range pages {
+		rec := p.record
+		source := rec.Source
+		if rel, err := filepath.Rel(env.searchRoot, rec.Source); err == nil {
+			source = filepath.ToSlash(rel)
+		}
+		doc.Pages = append(doc.Pages, manifestPage{
+			Source:      source,
+			Origin:      rec.Origin,
+			Category:    rec.CategoryPath,
+			Slug:        rec.Slug,
+			Title:       rec.Title,
+			URL:         b.engine.PageURL(rec),
+			Hash:        rec.Hash,
+			Hidden:      rec.FrontMatter.Hidden,
+			FrontMatter: rec.FrontMatter.Raw,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", manifestName, err)
+	}
+	target := filepath.Join(env.tempDir, manifestName)
+	if err := os.WriteFile(target, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// sitePath returns the site absolute URL path of rec. Index pages resolve to
+// their directory when dirIndex is set, every other page gets suffix appended.
+func sitePath(rec menuRecord, suffix string, dirIndex bool) string {
+	if rec.Slug == "index" && dirIndex {
+		dir := strings.Trim(rec.CategoryPath, "/")
+		if dir == "" {
+			return "/"
+		}
+		return "/" + dir + "/"
+	}
+	return "/" + strings.Trim(rec.CategoryPath+"/"+rec.Slug, "/") + suffix
+}
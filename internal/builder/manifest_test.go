@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWritesManifest(t *testing.T) {
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(searchDir, "docs")
+
+	writeTestFile(t, filepath.Join(searchDir, "api", "DOC_Api_Guide.md"), "---\ncategory: api\ntitle: API | Guide\n---\n# Ignored\n")
+	writeTestFile(t, filepath.Join(docDir, "guides", "setup.md"), "# Setup\n")
+	writeTestFile(t, filepath.Join(docDir, "index.md"), "# Welcome\n")
+
+	cfg := Config{Prefix: "DOC_", Engine: "html", SearchPath: searchDir, DocDir: docDir, TempDirName: "temp"}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(docDir, "temp", manifestName),
+		filepath.Join(docDir, "dist", manifestName),
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected manifest at %s: %v", path, err)
+		}
+		var doc manifest
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("invalid manifest %s: %v", path, err)
+		}
+		if doc.Engine != "html" || len(doc.Pages) != 3 {
+			t.Fatalf("unexpected manifest: %+v", doc)
+		}
+
+		pages := map[string]manifestPage{}
+		for _, page := range doc.Pages {
+			pages[page.Source] = page
+		}
+		api := pages["api/DOC_Api_Guide.md"]
+		if api.Origin != originPrefixed || api.Category != "api" || api.Slug != "api-guide" || api.Title != "API | Guide" {
+			t.Fatalf("unexpected prefixed page: %+v", api)
+		}
+		if api.URL != "/api/api-guide.html" || api.FrontMatter["category"] != "api" {
+			t.Fatalf("unexpected prefixed page url or front matter: %+v", api)
+		}
+		if api.Hash != contentHash([]byte("---\ncategory: api\ntitle: API | Guide\n---\n# Ignored\n")) {
+			t.Fatalf("unexpected hash %s", api.Hash)
+		}
+		setup := pages["docs/guides/setup.md"]
+		if setup.Origin != originExisting || setup.URL != "/guides/setup.html" {
+			t.Fatalf("unexpected existing page: %+v", setup)
+		}
+		home := pages["docs/index.md"]
+		if home.Slug != "index" || home.Title != "Welcome" {
+			t.Fatalf("unexpected home page: %+v", home)
+		}
+	}
+}
+
+func TestSitePath(t *testing.T) {
+	cases := []struct {
+		rec      menuRecord
+		suffix   string
+		dirIndex bool
+		want     string
+	}{
+		{menuRecord{CategoryPath: "a/b", Slug: "page"}, ".html", true, "/a/b/page.html"},
+		{menuRecord{CategoryPath: "a/b", Slug: "index"}, ".html", true, "/a/b/"},
+		{menuRecord{Slug: "index"}, "/", true, "/"},
+		{menuRecord{CategoryPath: "a", Slug: "index"}, ".html", false, "/a/index.html"},
+	}
+	for _, tc := range cases {
+		if got := sitePath(tc.rec, tc.suffix, tc.dirIndex); got != tc.want {
+			t.Fatalf("sitePath(%+v) = %q, want %q", tc.rec, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	registerEngine("mdbook", func(cfg Config) Engine {
+		return &mdbookEngine{cfg: cfg}
+	})
+}
+
+// mdbookEngine copies the pages into src/, generates SUMMARY.md from the
+// category tree and runs `mdbook build` with the book.toml of the doc dir.
+type mdbookEngine struct {
+	cfg Config
+}
+
+func (e *mdbookEngine) Name() string {
+	return "mdbook"
+}
+
+func (e *mdbookEngine) Prepare(env *environment) error {
+	if err := requireFile(filepath.Join(env.docDir, "book.toml")); err != nil {
+		return err
+	}
+	env.contentDir = filepath.Join(env.tempDir, "src")
+	return nil
+}
+
+func (e *mdbookEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Generating mdBook SUMMARY.md")
+	}
+
+	lines := []string{"# Summary", ""}
+	if _, err := os.Stat(filepath.Join(env.contentDir, "index.md")); err == nil {
+		lines = append(lines, "[Introduction](index.md)", "")
+	}
+	for _, sec := range sections {
+		lines = appendSummarySection(lines, sec, 0)
+	}
+	lines = append(lines, "")
+
+	target := filepath.Join(env.contentDir, "SUMMARY.md")
+	if err := os.MkdirAll(env.contentDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", env.contentDir, err)
+	}
+	if err := os.WriteFile(target, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func (e *mdbookEngine) Build(ctx context.Context, env environment) error {
+	if err := copyFile(filepath.Join(env.docDir, "book.toml"), filepath.Join(env.tempDir, "book.toml")); err != nil {
+		return err
+	}
+
+	binary := e.cfg.MdBook.Binary
+	if binary == "" {
+		binary = "mdbook"
+	}
+	if e.cfg.Verbose {
+		fmt.Printf("[5/7] Running %s build\n", binary)
+	}
+	return runTool(ctx, env, binary, "build", "--dest-dir", "book")
+}
+
+func (e *mdbookEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, "book"), filepath.Join(env.docDir, "book")
+}
+
+// publishesNavigationOnly marks that mdBook only renders the chapters listed in
+// SUMMARY.md, so hidden pages have to stay in it.
+func (e *mdbookEngine) publishesNavigationOnly() {}
+
+func (e *mdbookEngine) PageURL(rec menuRecord) string {
+	return sitePath(rec, ".html", false)
+}
+
+// appendSummarySection renders sec as a nested chapter list. Categories become
+// draft chapters unless they have an index page, which is then used as the
+// chapter itself.
+func appendSummarySection(lines []string, sec *section, depth int) []string {
+	indent := strings.Repeat("    ", depth)
+	link := ""
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			link = summaryPath(item)
+			break
+		}
+	}
+	lines = append(lines, fmt.Sprintf("%s- [%s](%s)", indent, escapeSummaryTitle(sec.Title), link))
+	for _, item := range sec.Items {
+		if item.Slug == "index" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s    - [%s](%s)", indent, escapeSummaryTitle(item.Title), summaryPath(item)))
+	}
+	for _, sub := range sec.Sections {
+		lines = appendSummarySection(lines, sub, depth+1)
+	}
+	return lines
+}
+
+func summaryPath(item menuRecord) string {
+	return strings.Trim(item.CategoryPath+"/"+item.Slug+".md", "/")
+}
+
+func escapeSummaryTitle(title string) string {
+	title = strings.ReplaceAll(title, "[", "\\[")
+	return strings.ReplaceAll(title, "]", "\\]")
+}
@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMdBookEngineWritesNestedSummary(t *testing.T) {
+	docDir := t.TempDir()
+	writeTestFile(t, filepath.Join(docDir, "book.toml"), "[book]\ntitle = \"Example\"\n")
+
+	engine := &mdbookEngine{}
+	env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")}
+	if err := engine.Prepare(&env); err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	writeTestFile(t, filepath.Join(env.contentDir, "index.md"), "# Home\n")
+
+	records := []menuRecord{
+		{CategoryPath: "guides", Slug: "index", Title: "Guides Home"},
+		{CategoryPath: "guides", Slug: "setup", Title: "Setup [beta]"},
+		{CategoryPath: "architecture/components/core", Slug: "core-services", Title: "Core Services"},
+	}
+	if err := engine.RenderNavigation(env, buildSections(records, nil)); err != nil {
+		t.Fatalf("RenderNavigation returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.contentDir, "SUMMARY.md"))
+	if err != nil {
+		t.Fatalf("expected SUMMARY.md: %v", err)
+	}
+	expected := strings.Join([]string{
+		"# Summary",
+		"",
+		"[Introduction](index.md)",
+		"",
+		"- [Architecture]()",
+		"    - [Components]()",
+		"        - [Core]()",
+		"            - [Core Services](architecture/components/core/core-services.md)",
+		"- [Guides](guides/index.md)",
+		"    - [Setup \\[beta\\]](guides/setup.md)",
+		"",
+	}, "\n")
+	if string(data) != expected {
+		t.Fatalf("unexpected SUMMARY.md:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestMdBookEngineRequiresBookToml(t *testing.T) {
+	env := environment{docDir: t.TempDir()}
+	err := (&mdbookEngine{}).Prepare(&env)
+	if err == nil || !strings.Contains(err.Error(), "book.toml") {
+		t.Fatalf("expected missing book.toml error, got %v", err)
+	}
+}
+
+func TestMdBookEngineKeepsHiddenPagesInSummary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executable relies on a POSIX shell")
+	}
+
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "docs")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Setup.md"), "# Setup\n")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Legacy.md"), "---\nhidden: true\n---\n# Legacy\n")
+	writeTestFile(t, filepath.Join(docDir, "book.toml"), "[book]\ntitle = \"Example\"\n")
+
+	fake := filepath.Join(root, "fake-mdbook")
+	writeTestFile(t, fake, "#!/bin/sh\nset -e\nmkdir -p \"$3\"\ncp src/SUMMARY.md \"$3/SUMMARY.md\"\n")
+	if err := os.Chmod(fake, 0o755); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+
+	cfg := Config{Prefix: "DOC_", Engine: "mdbook", SearchPath: searchDir, DocDir: docDir, TempDirName: "temp", MdBook: MdBookConfig{Binary: fake}}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(docDir, "book", "SUMMARY.md"))
+	if err != nil {
+		t.Fatalf("expected published summary: %v", err)
+	}
+	if !strings.Contains(string(data), "[Legacy](guides/legacy.md)") {
+		t.Fatalf("expected hidden page to stay in SUMMARY.md so it is published:\n%s", data)
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(docDir, "book", manifestName))
+	if err != nil {
+		t.Fatalf("expected manifest: %v", err)
+	}
+	var doc manifest
+	if err := json.Unmarshal(manifestData, &doc); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	for _, page := range doc.Pages {
+		if page.Hidden {
+			t.Fatalf("expected manifest to match the navigation, %s is marked hidden", page.Source)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mkdocsPlaceholder = "# NAV_ITEMS - will be replaced by build script"
+
+func init() {
+	registerEngine("mkdocs", func(cfg Config) Engine {
+		return &mkdocsEngine{cfg: cfg}
+	})
+}
+
+// mkdocsEngine writes the pages into docs/, merges a generated nav: section
+// into the base mkdocs.yml and runs `mkdocs build`.
+type mkdocsEngine struct {
+	cfg   Config
+	files mkdocsProjectFiles
+}
+
+// mkdocsProjectFiles are the paths the base mkdocs.yml refers to, relative to
+// the documentation directory.
+type mkdocsProjectFiles struct {
+	customDir string
+	hooks     []string
+	// extras are extra_css and extra_javascript files, which MkDocs looks up
+	// inside docs_dir.
+	extras []string
+}
+
+func (e *mkdocsEngine) Name() string {
+	return "mkdocs"
+}
+
+func (e *mkdocsEngine) Prepare(env *environment) error {
+	baseConfig := e.baseConfig(*env)
+	if err := requireFile(baseConfig); err != nil {
+		return err
+	}
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read base config %s: %w", baseConfig, err)
+	}
+	if e.files, err = readMkDocsProjectFiles(data); err != nil {
+		return fmt.Errorf("failed to parse base config %s: %w", baseConfig, err)
+	}
+
+	env.contentDir = filepath.Join(env.tempDir, "docs")
+	if e.files.customDir != "" {
+		env.reservedDirs = append(env.reservedDirs, filepath.Join(env.docDir, e.files.customDir))
+	}
+	return nil
+}
+
+func (e *mkdocsEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Generating MkDocs nav configuration")
+	}
+
+	baseConfig := e.baseConfig(env)
+	baseData, err := os.ReadFile(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read base config %s: %w", baseConfig, err)
+	}
+
+	var entries []any
+	if _, err := os.Stat(filepath.Join(env.contentDir, "index.md")); err == nil {
+		entries = append(entries, map[string]any{"Home": "index.md"})
+	}
+	for _, sec := range sections {
+		entries = append(entries, mkdocsSectionNav(sec))
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]any{"nav": entries}); err != nil {
+		return fmt.Errorf("failed to encode mkdocs nav: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode mkdocs nav: %w", err)
+	}
+
+	output := setMkDocsDocsDir(mergeMkDocsNav(string(baseData), strings.TrimRight(buf.String(), "\n")))
+	target := filepath.Join(env.tempDir, "mkdocs.yml")
+	if err := os.WriteFile(target, []byte(output), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+func (e *mkdocsEngine) Build(ctx context.Context, env environment) error {
+	if e.files.customDir != "" {
+		if err := copyDirectory(filepath.Join(env.docDir, e.files.customDir), filepath.Join(env.tempDir, e.files.customDir)); err != nil {
+			return err
+		}
+	}
+	for _, hook := range e.files.hooks {
+		if err := copyFile(filepath.Join(env.docDir, hook), filepath.Join(env.tempDir, hook)); err != nil {
+			return err
+		}
+	}
+	for _, extra := range e.files.extras {
+		src := filepath.Join(env.docDir, extra)
+		if !fileExists(src) {
+			continue
+		}
+		if err := copyFile(src, filepath.Join(env.contentDir, extra)); err != nil {
+			return err
+		}
+	}
+
+	binary := e.cfg.MkDocs.Binary
+	if binary == "" {
+		binary = "mkdocs"
+	}
+	if e.cfg.Verbose {
+		fmt.Printf("[5/7] Running %s build\n", binary)
+	}
+	return runTool(ctx, env, binary, "build", "--site-dir", "site")
+}
+
+func (e *mkdocsEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, "site"), filepath.Join(env.docDir, "site")
+}
+
+func (e *mkdocsEngine) PageURL(rec menuRecord) string {
+	return sitePath(rec, "/", true)
+}
+
+func (e *mkdocsEngine) baseConfig(env environment) string {
+	return filepath.Join(env.docDir, "mkdocs.yml")
+}
+
+func mkdocsSectionNav(sec *section) map[string]any {
+	items := make([]any, 0, len(sec.Items)+len(sec.Sections))
+	for _, item := range sec.Items {
+		path := strings.Trim(item.CategoryPath+"/"+item.Slug+".md", "/")
+		items = append(items, map[string]any{item.Title: path})
+	}
+	for _, sub := range sec.Sections {
+		items = append(items, mkdocsSectionNav(sub))
+	}
+	return map[string]any{sec.Title: items}
+}
+
+// mergeMkDocsNav injects nav into the base config. The placeholder comment wins
+// when present, otherwise an existing top-level nav: block is replaced and, as a
+// last resort, the generated block is appended.
+func mergeMkDocsNav(base, nav string) string {
+	if strings.Contains(base, mkdocsPlaceholder) {
+		return strings.Replace(base, mkdocsPlaceholder, nav, 1)
+	}
+
+	lines := strings.Split(base, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "nav:") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return strings.TrimRight(base, "\n") + "\n\n" + nav + "\n"
+	}
+
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "-") {
+			break
+		}
+		end++
+	}
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	merged := make([]string, 0, len(lines))
+	merged = append(merged, lines[:start]...)
+	merged = append(merged, strings.Split(nav, "\n")...)
+	merged = append(merged, lines[end:]...)
+	return strings.Join(merged, "\n")
+}
+
+// readMkDocsProjectFiles collects the local paths referenced by mkdocs.yml. Tags
+// such as !!python/name are kept undecoded.
+func readMkDocsProjectFiles(data []byte) (mkdocsProjectFiles, error) {
+	var files mkdocsProjectFiles
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return files, err
+	}
+	if len(doc.Content) == 0 {
+		return files, nil
+	}
+	root := doc.Content[0]
+
+	if theme := yamlMapValue(root, "theme"); theme != nil {
+		if dir := yamlMapValue(theme, "custom_dir"); dir != nil && dir.Kind == yaml.ScalarNode {
+			files.customDir = localMkDocsPath(dir.Value)
+		}
+	}
+	for _, item := range yamlSequence(yamlMapValue(root, "hooks")) {
+		if path := localMkDocsPath(item.Value); path != "" {
+			files.hooks = append(files.hooks, path)
+		}
+	}
+	for _, key := range []string{"extra_css", "extra_javascript"} {
+		for _, item := range yamlSequence(yamlMapValue(root, key)) {
+			if item.Kind == yaml.MappingNode {
+				item = yamlMapValue(item, "path")
+			}
+			if item == nil {
+				continue
+			}
+			if path := localMkDocsPath(item.Value); path != "" {
+				files.extras = append(files.extras, path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// localMkDocsPath returns value as a clean relative path, or an empty string
+// for URLs and paths outside the documentation directory.
+func localMkDocsPath(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.Contains(value, "://") || strings.HasPrefix(value, "/") {
+		return ""
+	}
+	cleaned := filepath.Clean(filepath.FromSlash(value))
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return cleaned
+}
+
+func yamlMapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func yamlSequence(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// setMkDocsDocsDir points docs_dir at the generated docs/ tree, replacing any
+// value from the base config.
+func setMkDocsDocsDir(config string) string {
+	lines := strings.Split(strings.TrimRight(config, "\n"), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "docs_dir:") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(append(kept, "docs_dir: docs"), "\n") + "\n"
+}
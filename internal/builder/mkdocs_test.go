@@ -0,0 +1,138 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMergeMkDocsNav(t *testing.T) {
+	nav := "nav:\n  - Home: index.md"
+
+	withPlaceholder := "site_name: Docs\n" + mkdocsPlaceholder + "\ntheme: material\n"
+	if got := mergeMkDocsNav(withPlaceholder, nav); got != "site_name: Docs\n"+nav+"\ntheme: material\n" {
+		t.Fatalf("expected placeholder replacement, got %q", got)
+	}
+
+	withNav := "site_name: Docs\nnav:\n  - Old: old.md\n  - Other: other.md\n\ntheme:\n  name: material\n"
+	expected := "site_name: Docs\n" + nav + "\n\ntheme:\n  name: material\n"
+	if got := mergeMkDocsNav(withNav, nav); got != expected {
+		t.Fatalf("expected nav key replacement, got %q", got)
+	}
+
+	withoutNav := "site_name: Docs\n"
+	if got := mergeMkDocsNav(withoutNav, nav); got != "site_name: Docs\n\n"+nav+"\n" {
+		t.Fatalf("expected nav to be appended, got %q", got)
+	}
+}
+
+func TestMkDocsEngineBuildsWithFakeExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executable relies on a POSIX shell")
+	}
+
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "docs")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Core_Services.md"), "---\ncategory: architecture/components/core\ntitle: Core Services\n---\n# Core Services\n")
+	writeTestFile(t, filepath.Join(docDir, "index.md"), "# Home\n")
+	writeTestFile(t, filepath.Join(docDir, "mkdocs.yml"), "site_name: Example\n"+mkdocsPlaceholder+"\n")
+
+	fake := filepath.Join(root, "fake-mkdocs")
+	writeTestFile(t, fake, "#!/bin/sh\nset -e\ntest \"$1\" = build\nmkdir -p \"$3\"\ncp mkdocs.yml \"$3/nav.yml\"\n")
+	if err := os.Chmod(fake, 0o755); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+
+	cfg := Config{
+		Prefix:      "DOC_",
+		Engine:      "mkdocs",
+		SearchPath:  searchDir,
+		DocDir:      docDir,
+		TempDirName: "temp",
+		MkDocs:      MkDocsConfig{Binary: fake},
+	}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(docDir, "temp", "docs", "architecture", "components", "core", "core-services.md")); err != nil {
+		t.Fatalf("expected page inside docs/ tree: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(docDir, "site", "nav.yml"))
+	if err != nil {
+		t.Fatalf("expected site output to be published: %v", err)
+	}
+	for _, expect := range []string{
+		"site_name: Example",
+		"- Home: index.md",
+		"- Architecture:",
+		"- Core Services: architecture/components/core/core-services.md",
+	} {
+		if !strings.Contains(string(data), expect) {
+			t.Fatalf("expected generated mkdocs.yml to contain %q, got:\n%s", expect, data)
+		}
+	}
+}
+
+func TestMkDocsEngineCopiesProjectFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executable relies on a POSIX shell")
+	}
+
+	root := t.TempDir()
+	searchDir := filepath.Join(root, "src")
+	docDir := filepath.Join(root, "docs")
+	writeTestFile(t, filepath.Join(searchDir, "DOC_Setup.md"), "# Setup\n")
+	writeTestFile(t, filepath.Join(docDir, "mkdocs.yml"), strings.Join([]string{
+		"site_name: Example",
+		"docs_dir: content",
+		"theme:",
+		"  name: material",
+		"  custom_dir: overrides",
+		"hooks:",
+		"  - hooks/build.py",
+		"extra_css:",
+		"  - stylesheets/extra.css",
+		"extra_javascript:",
+		"  - path: js/app.js",
+		"  - https://cdn.example.com/lib.js",
+		"markdown_extensions:",
+		"  - pymdownx.emoji:",
+		"      emoji_index: !!python/name:material.extensions.emoji.twemoji",
+		"",
+	}, "\n"))
+	writeTestFile(t, filepath.Join(docDir, "overrides", "main.html"), "{% extends \"base.html\" %}\n")
+	writeTestFile(t, filepath.Join(docDir, "overrides", "partials", "notes.md"), "# Theme Notes\n")
+	writeTestFile(t, filepath.Join(docDir, "hooks", "build.py"), "def on_config(config): pass\n")
+	writeTestFile(t, filepath.Join(docDir, "stylesheets", "extra.css"), "body {}\n")
+	writeTestFile(t, filepath.Join(docDir, "js", "app.js"), "console.log(1)\n")
+
+	fake := filepath.Join(root, "fake-mkdocs")
+	script := "#!/bin/sh\nset -e\ntest -f overrides/main.html\ntest -f hooks/build.py\ntest -f docs/stylesheets/extra.css\ntest -f docs/js/app.js\nmkdir -p \"$3\"\ncp mkdocs.yml \"$3/mkdocs.yml\"\n"
+	writeTestFile(t, fake, script)
+	if err := os.Chmod(fake, 0o755); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+
+	cfg := Config{Prefix: "DOC_", Engine: "mkdocs", SearchPath: searchDir, DocDir: docDir, TempDirName: "temp", MkDocs: MkDocsConfig{Binary: fake}}
+	if err := New(cfg).Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(docDir, "site", "mkdocs.yml"))
+	if err != nil {
+		t.Fatalf("expected site output to be published: %v", err)
+	}
+	config := string(data)
+	if !strings.Contains(config, "docs_dir: docs") || strings.Contains(config, "docs_dir: content") {
+		t.Fatalf("expected docs_dir to point at the generated tree:\n%s", config)
+	}
+	if strings.Contains(config, "Theme Notes") {
+		t.Fatalf("expected theme overrides to stay out of the nav:\n%s", config)
+	}
+}
@@ -2,94 +2,276 @@ package builder
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 )
 
-func (b *Builder) installDependencies(ctx context.Context, env environment) error {
-	if b.cfg.Verbose {
+// nodeManifests are copied into the workspace and, when present, make up the
+// dependency cache key.
+var nodeManifests = []string{
+	"package.json",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"bun.lock",
+	"bun.lockb",
+	".npmrc",
+	".yarnrc.yml",
+}
+
+// packageManager describes how dependencies are installed and scripts are run
+// for a documentation workspace.
+type packageManager struct {
+	Name    string
+	Install []string
+}
+
+// lockfileManagers maps lockfiles to the package manager that writes them, in
+// detection order.
+var lockfileManagers = []struct {
+	lockfile string
+	manager  string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"yarn.lock", "yarn"},
+	{"bun.lock", "bun"},
+	{"bun.lockb", "bun"},
+	{"package-lock.json", "npm"},
+	{"npm-shrinkwrap.json", "npm"},
+}
+
+// installDependencies copies the Node.js manifests from the documentation
+// directory into the workspace and links node_modules from the dependency
+// cache, running a lockfile-faithful install only when the manifests changed.
+// It is shared by all engines built on top of a Node.js package manager.
+func installDependencies(ctx context.Context, cfg Config, env environment) (packageManager, error) {
+	if cfg.Verbose {
 		fmt.Println("[5/7] Preparing Node.js dependencies")
 	}
 
-	filesToCopy := []string{"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml"}
-	for _, name := range filesToCopy {
+	for _, name := range nodeManifests {
 		src := filepath.Join(env.docDir, name)
 		if _, err := os.Stat(src); err == nil {
 			dst := filepath.Join(env.tempDir, name)
 			if err := copyFile(src, dst); err != nil {
-				return err
+				return packageManager{}, err
 			}
 		}
 	}
+	releases := filepath.Join(env.docDir, ".yarn", "releases")
+	if info, err := os.Stat(releases); err == nil && info.IsDir() {
+		if err := copyDirectory(releases, filepath.Join(env.tempDir, ".yarn", "releases")); err != nil {
+			return packageManager{}, err
+		}
+	}
+
+	pm, err := detectPackageManager(cfg.PackageManager, env.tempDir)
+	if err != nil {
+		return packageManager{}, err
+	}
+	if cfg.Verbose {
+		fmt.Printf("  using %s\n", strings.Join(pm.Install, " "))
+	}
 
+	key, err := dependencyCacheKey(env.tempDir, pm)
+	if err != nil {
+		return packageManager{}, err
+	}
+	cacheRoot, err := dependencyCacheDir(cfg)
+	if err != nil {
+		return packageManager{}, err
+	}
+	cached := filepath.Join(cacheRoot, key, "node_modules")
 	nodeModules := filepath.Join(env.tempDir, "node_modules")
-	if _, err := os.Stat(nodeModules); err == nil {
-		if b.cfg.Verbose {
-			fmt.Println("  node_modules already present, skipping npm install")
+
+	if info, err := os.Stat(cached); err == nil && info.IsDir() {
+		if cfg.Verbose {
+			fmt.Printf("  reusing cached node_modules %s\n", cached)
 		}
-		return nil
+		return pm, linkNodeModules(cached, nodeModules)
 	}
 
-	if b.cfg.Verbose {
-		fmt.Println("  running npm install (this may take a while)")
+	if cfg.Verbose {
+		fmt.Printf("  running %s (this may take a while)\n", strings.Join(pm.Install, " "))
+	}
+	if err := runTool(ctx, env, pm.Install[0], pm.Install[1:]...); err != nil {
+		return packageManager{}, err
 	}
 
-	//nolint:gosec // npm install is safe in controlled environment
-	cmd := exec.CommandContext(ctx, "npm", "install")
-	cmd.Dir = env.tempDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("npm install failed: %w", err)
+	if _, err := os.Stat(nodeModules); errors.Is(err, os.ErrNotExist) {
+		// Plug'n'Play installs do not produce node_modules; nothing to cache.
+		return pm, nil
 	}
-	return nil
+	if err := storeNodeModules(nodeModules, cached); err != nil {
+		return packageManager{}, err
+	}
+	if cfg.Verbose {
+		fmt.Printf("  cached node_modules in %s\n", cached)
+	}
+	return pm, linkNodeModules(cached, nodeModules)
 }
 
-func (b *Builder) buildSite(ctx context.Context, env environment) error {
-	if b.cfg.Verbose {
-		fmt.Println("[6/7] Running npm run docs:build")
+func runPackageScript(ctx context.Context, cfg Config, env environment, pm packageManager, script string) error {
+	if cfg.Verbose {
+		fmt.Printf("[6/7] Running %s run %s\n", pm.Name, script)
 	}
 
-	//nolint:gosec // npm run is safe in controlled environment
-	cmd := exec.CommandContext(ctx, "npm", "run", "docs:build")
-	cmd.Dir = env.tempDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("npm run docs:build failed: %w", err)
-	}
-	return nil
+	return runTool(ctx, env, pm.Name, "run", script)
 }
 
-func (b *Builder) publishDist(env environment) error {
-	if b.cfg.Verbose {
-		fmt.Println("[7/7] Publishing VitePress dist output")
+// detectPackageManager picks the package manager from the explicit setting,
+// the packageManager field of package.json or the lockfile present in dir, in
+// that order, and falls back to npm.
+func detectPackageManager(configured, dir string) (packageManager, error) {
+	name, version := strings.ToLower(strings.TrimSpace(configured)), ""
+
+	if name == "" {
+		//nolint:gosec // file path is validated and safe
+		data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return packageManager{}, fmt.Errorf("failed to read package.json: %w", err)
+		}
+		if err == nil {
+			var manifest struct {
+				PackageManager string `json:"packageManager"`
+			}
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return packageManager{}, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "package.json"), err)
+			}
+			name, version, _ = strings.Cut(manifest.PackageManager, "@")
+			name = strings.ToLower(strings.TrimSpace(name))
+		}
 	}
 
-	if _, err := os.Stat(env.distSrc); err != nil {
+	if name == "" {
+		for _, candidate := range lockfileManagers {
+			if fileExists(filepath.Join(dir, candidate.lockfile)) {
+				name = candidate.manager
+				break
+			}
+		}
+	}
+	if name == "" {
+		name = "npm"
+	}
+
+	switch name {
+	case "npm":
+		if fileExists(filepath.Join(dir, "package-lock.json")) || fileExists(filepath.Join(dir, "npm-shrinkwrap.json")) {
+			return packageManager{Name: "npm", Install: []string{"npm", "ci"}}, nil
+		}
+		return packageManager{Name: "npm", Install: []string{"npm", "install"}}, nil
+	case "pnpm":
+		if fileExists(filepath.Join(dir, "pnpm-lock.yaml")) {
+			return packageManager{Name: "pnpm", Install: []string{"pnpm", "install", "--frozen-lockfile"}}, nil
+		}
+		return packageManager{Name: "pnpm", Install: []string{"pnpm", "install"}}, nil
+	case "yarn":
+		if !fileExists(filepath.Join(dir, "yarn.lock")) {
+			return packageManager{Name: "yarn", Install: []string{"yarn", "install"}}, nil
+		}
+		berry := fileExists(filepath.Join(dir, ".yarnrc.yml"))
+		if major, _, _ := strings.Cut(version, "."); major != "" && major != "1" {
+			berry = true
+		}
+		if berry {
+			return packageManager{Name: "yarn", Install: []string{"yarn", "install", "--immutable"}}, nil
+		}
+		return packageManager{Name: "yarn", Install: []string{"yarn", "install", "--frozen-lockfile"}}, nil
+	case "bun":
+		if fileExists(filepath.Join(dir, "bun.lock")) || fileExists(filepath.Join(dir, "bun.lockb")) {
+			return packageManager{Name: "bun", Install: []string{"bun", "install", "--frozen-lockfile"}}, nil
+		}
+		return packageManager{Name: "bun", Install: []string{"bun", "install"}}, nil
+	}
+	return packageManager{}, fmt.Errorf("unsupported package manager '%s': expected npm, pnpm, yarn or bun", name)
+}
+
+// dependencyCacheKey hashes the dependency manifests found in dir together with
+// the install command and platform, so entries are never shared across package
+// managers or systems.
+func dependencyCacheKey(dir string, pm packageManager) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(hash, "%s\n", strings.Join(pm.Install, " "))
+	for _, name := range nodeManifests {
+		//nolint:gosec // file path is validated and safe
+		data, err := os.ReadFile(filepath.Join(dir, name))
 		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("expected directory not found: %s", env.distSrc)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
 		}
-		return fmt.Errorf("failed to access dist directory: %w", err)
+		fmt.Fprintf(hash, "%s %d\n", name, len(data))
+		hash.Write(data)
 	}
+	return hex.EncodeToString(hash.Sum(nil))[:32], nil
+}
 
-	if err := os.RemoveAll(env.distDst); err != nil {
-		return fmt.Errorf("failed to clean %s: %w", env.distDst, err)
+func dependencyCacheDir(cfg Config) (string, error) {
+	if cfg.CacheDir != "" {
+		dir, err := filepath.Abs(cfg.CacheDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve cache directory: %w", err)
+		}
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory (set --cache-dir): %w", err)
 	}
+	return filepath.Join(base, "doc-builder", "node_modules"), nil
+}
 
-	if err := copyDirectory(env.distSrc, env.distDst); err != nil {
-		return err
+// storeNodeModules moves a freshly installed node_modules into the cache. The
+// directory is staged next to its final location so concurrent builds never
+// observe a partially written entry.
+func storeNodeModules(src, cached string) error {
+	if err := os.MkdirAll(filepath.Dir(cached), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory %s: %w", filepath.Dir(cached), err)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(cached), ".staging-")
+	if err != nil {
+		return fmt.Errorf("failed to create cache staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	staged := filepath.Join(staging, "node_modules")
+	if err := os.Rename(src, staged); err != nil {
+		if err := copyDirectory(src, staged); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(src); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", src, err)
+		}
+	}
+	if err := os.Rename(staged, cached); err != nil {
+		if _, statErr := os.Stat(cached); statErr == nil {
+			// Another build populated the same entry first; keep that one.
+			return nil
+		}
+		return fmt.Errorf("failed to store node_modules in %s: %w", cached, err)
 	}
 	return nil
 }
 
-func (b *Builder) printSummary(env environment, prefCount, existingCount, menuCount int) {
-	fmt.Println("Build complete.")
-	fmt.Printf("  Found %d prefixed markdown files\n", prefCount)
-	fmt.Printf("  Merged %d existing documentation files\n", existingCount)
-	fmt.Printf("  Sidebar entries: %d\n", menuCount)
-	fmt.Printf("  Output directory: %s\n", env.distDst)
+// linkNodeModules exposes the cached node_modules inside the workspace, falling
+// back to a copy where symbolic links are not available.
+func linkNodeModules(cached, target string) error {
+	if err := os.RemoveAll(target); err != nil {
+		return fmt.Errorf("failed to clean %s: %w", target, err)
+	}
+	if err := os.Symlink(cached, target); err == nil {
+		return nil
+	}
+	return copyDirectory(cached, target)
 }
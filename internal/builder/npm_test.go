@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstallDependenciesReusesCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake npm relies on a POSIX shell")
+	}
+
+	root := t.TempDir()
+	binDir := filepath.Join(root, "bin")
+	counter := filepath.Join(root, "installs")
+	writeTestFile(t, filepath.Join(binDir, "npm"), "#!/bin/sh\nset -e\necho \"$@\" >> "+counter+"\nmkdir -p node_modules/.bin\necho ok > node_modules/marker\n")
+	if err := os.Chmod(filepath.Join(binDir, "npm"), 0o755); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	docDir := filepath.Join(root, "docs")
+	writeTestFile(t, filepath.Join(docDir, "package.json"), `{"devDependencies":{"vitepress":"^1.6.4"}}`)
+	writeTestFile(t, filepath.Join(docDir, "package-lock.json"), `{"lockfileVersion":3}`)
+	cfg := Config{CacheDir: filepath.Join(root, "cache")}
+
+	install := func() {
+		t.Helper()
+		env := environment{docDir: docDir, tempDir: filepath.Join(docDir, "temp")}
+		if err := os.RemoveAll(env.tempDir); err != nil {
+			t.Fatalf("cleanup failed: %v", err)
+		}
+		if err := os.MkdirAll(env.tempDir, 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		pm, err := installDependencies(context.Background(), cfg, env)
+		if err != nil {
+			t.Fatalf("installDependencies returned error: %v", err)
+		}
+		if strings.Join(pm.Install, " ") != "npm ci" {
+			t.Fatalf("expected lockfile-faithful npm ci, got %v", pm.Install)
+		}
+		if data, err := os.ReadFile(filepath.Join(env.tempDir, "node_modules", "marker")); err != nil || string(data) != "ok\n" {
+			t.Fatalf("expected node_modules in workspace, got %q (err=%v)", data, err)
+		}
+	}
+	installs := func() int {
+		data, err := os.ReadFile(counter)
+		if err != nil {
+			return 0
+		}
+		return strings.Count(string(data), "\n")
+	}
+
+	install()
+	install()
+	if got := installs(); got != 1 {
+		t.Fatalf("expected a single npm install with a warm cache, got %d", got)
+	}
+
+	writeTestFile(t, filepath.Join(docDir, "package-lock.json"), `{"lockfileVersion":3,"packages":{}}`)
+	install()
+	if got := installs(); got != 2 {
+		t.Fatalf("expected lockfile change to invalidate the cache, got %d installs", got)
+	}
+
+	entries, err := os.ReadDir(cfg.CacheDir)
+	if err != nil {
+		t.Fatalf("expected cache directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected one cache entry per lockfile revision, got %d", len(entries))
+	}
+}
+
+func TestDependencyCacheKeyTracksManifests(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "package.json"), "{}")
+	npm := packageManager{Name: "npm", Install: []string{"npm", "install"}}
+	first, err := dependencyCacheKey(dir, npm)
+	if err != nil {
+		t.Fatalf("dependencyCacheKey returned error: %v", err)
+	}
+	again, _ := dependencyCacheKey(dir, npm)
+	if first != again {
+		t.Fatalf("expected stable key, got %q and %q", first, again)
+	}
+
+	pnpm := packageManager{Name: "pnpm", Install: []string{"pnpm", "install"}}
+	if other, _ := dependencyCacheKey(dir, pnpm); other == first {
+		t.Fatalf("expected package manager to change the cache key")
+	}
+
+	writeTestFile(t, filepath.Join(dir, "yarn.lock"), "# yarn lockfile v1\n")
+	withLock, _ := dependencyCacheKey(dir, npm)
+	if withLock == first {
+		t.Fatalf("expected lockfile to change the cache key")
+	}
+}
+
+func TestDetectPackageManager(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured string
+		files      map[string]string
+		expected   string
+	}{
+		{"default", "", map[string]string{"package.json": "{}"}, "npm install"},
+		{"npm lockfile", "", map[string]string{"package-lock.json": "{}"}, "npm ci"},
+		{"pnpm lockfile", "", map[string]string{"pnpm-lock.yaml": ""}, "pnpm install --frozen-lockfile"},
+		{"yarn classic", "", map[string]string{"yarn.lock": ""}, "yarn install --frozen-lockfile"},
+		{"yarn berry rc", "", map[string]string{"yarn.lock": "", ".yarnrc.yml": ""}, "yarn install --immutable"},
+		{"bun lockfile", "", map[string]string{"bun.lockb": ""}, "bun install --frozen-lockfile"},
+		{"package manager field", "", map[string]string{"package.json": `{"packageManager":"yarn@4.1.0"}`, "yarn.lock": "", "package-lock.json": "{}"}, "yarn install --immutable"},
+		{"configured wins", "pnpm", map[string]string{"package.json": `{"packageManager":"yarn@4.1.0"}`, "pnpm-lock.yaml": ""}, "pnpm install --frozen-lockfile"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+			pm, err := detectPackageManager(tc.configured, dir)
+			if err != nil {
+				t.Fatalf("detectPackageManager returned error: %v", err)
+			}
+			if got := strings.Join(pm.Install, " "); got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := detectPackageManager("deno", t.TempDir()); err == nil {
+		t.Fatalf("expected unsupported package manager to be rejected")
+	}
+}
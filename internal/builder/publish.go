@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func (b *Builder) publishDist(env environment) error {
+	distSrc, distDst := b.engine.Output(env)
+	if b.cfg.Verbose {
+		fmt.Printf("[7/7] Publishing %s output to %s\n", b.engine.Name(), distDst)
+	}
+
+	if _, err := os.Stat(distSrc); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("expected directory not found: %s", distSrc)
+		}
+		return fmt.Errorf("failed to access dist directory: %w", err)
+	}
+
+	if err := os.RemoveAll(distDst); err != nil {
+		return fmt.Errorf("failed to clean %s: %w", distDst, err)
+	}
+
+	if err := copyDirectory(distSrc, distDst); err != nil {
+		return err
+	}
+	return copyFile(filepath.Join(env.tempDir, manifestName), filepath.Join(distDst, manifestName))
+}
+
+// buildStats collects the counters reported after a successful build.
+type buildStats struct {
+	prefixed        int
+	existing        int
+	generated       int
+	entries         int
+	collisions      int
+	unresolvedLinks int
+	missingAssets   int
+	redirects       int
+	skipped         []skippedPage
+}
+
+func (b *Builder) printSummary(env environment, stats buildStats) {
+	_, distDst := b.engine.Output(env)
+	fmt.Println("Build complete.")
+	fmt.Printf("  Found %d prefixed markdown files\n", stats.prefixed)
+	fmt.Printf("  Merged %d existing documentation files\n", stats.existing)
+	if stats.generated > 0 {
+		fmt.Printf("  Generated %d API reference pages\n", stats.generated)
+	}
+	fmt.Printf("  Sidebar entries: %d\n", stats.entries)
+	if stats.collisions > 0 {
+		fmt.Printf("  Slug collisions: %d\n", stats.collisions)
+	}
+	if stats.unresolvedLinks > 0 {
+		fmt.Printf("  Unresolved links: %d\n", stats.unresolvedLinks)
+	}
+	if stats.missingAssets > 0 {
+		fmt.Printf("  Missing assets: %d\n", stats.missingAssets)
+	}
+	if stats.redirects > 0 {
+		fmt.Printf("  Redirects: %d\n", stats.redirects)
+	}
+	if len(stats.skipped) > 0 {
+		fmt.Printf("  Skipped pages: %d\n", len(stats.skipped))
+		for _, s := range stats.skipped {
+			fmt.Printf("    %s: %s\n", displayPath(s.Source), s.Reason)
+		}
+	}
+	fmt.Printf("  Output directory: %s\n", distDst)
+	fmt.Printf("  Manifest: %s\n", filepath.Join(distDst, manifestName))
+}
@@ -0,0 +1,135 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// redirect is a stub page published at an alias of a page.
+type redirect struct {
+	Path   string
+	Target string
+	Alias  string
+	Source string
+}
+
+func (b *Builder) collectRedirects(pages []page) ([]redirect, error) {
+	owners := map[string]string{}
+	for _, p := range pages {
+		owners[urlKey(b.engine.PageURL(p.record))] = p.record.Source
+	}
+
+	var redirects []redirect
+	var conflicts []string
+	aliases := map[string]string{}
+	for _, p := range pages {
+		for _, alias := range p.record.FrontMatter.Aliases {
+			file, err := redirectPath(alias)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", displayPath(p.record.Source), err)
+			}
+			key := urlKey(alias)
+			if owner, ok := owners[key]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("  alias %s of %s is the URL of %s", alias, displayPath(p.record.Source), displayPath(owner)))
+				continue
+			}
+			if owner, ok := aliases[key]; ok {
+				if owner != p.record.Source {
+					conflicts = append(conflicts, fmt.Sprintf("  alias %s is claimed by %s and %s", alias, displayPath(owner), displayPath(p.record.Source)))
+				}
+				continue
+			}
+			aliases[key] = p.record.Source
+
+			redirects = append(redirects, redirect{Path: file, Target: relativeRedirectTarget(file, b.engine.PageURL(p.record)), Alias: alias, Source: p.record.Source})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("found %d alias conflicts:\n%s", len(conflicts), strings.Join(conflicts, "\n"))
+	}
+
+	sort.Slice(redirects, func(i, j int) bool { return redirects[i].Path < redirects[j].Path })
+	return redirects, nil
+}
+
+func (b *Builder) writeRedirects(env environment, redirects []redirect) error {
+	distSrc, _ := b.engine.Output(env)
+	for _, r := range redirects {
+		target := filepath.Join(distSrc, filepath.FromSlash(r.Path))
+		if _, err := os.Stat(target); err == nil {
+			if b.cfg.Verbose {
+				fmt.Printf("  redirect %s already exists, keeping it\n", target)
+			}
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to access %s: %w", target, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, []byte(redirectPage(r.Target)), 0o644); err != nil {
+			return fmt.Errorf("failed to write redirect %s: %w", target, err)
+		}
+		if b.cfg.Verbose {
+			fmt.Printf("  redirect %s -> %s\n", r.Alias, r.Target)
+		}
+	}
+	return nil
+}
+
+func redirectPath(alias string) (string, error) {
+	cleaned := path.Clean("/" + strings.TrimSpace(alias))
+	if cleaned == "/" || strings.Contains(alias, "..") || strings.ContainsAny(alias, "?#") {
+		return "", fmt.Errorf("invalid alias '%s'", alias)
+	}
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if strings.HasSuffix(cleaned, ".html") {
+		return cleaned, nil
+	}
+	return strings.TrimSuffix(cleaned, ".md") + "/index.html", nil
+}
+
+func relativeRedirectTarget(file, url string) string {
+	target := strings.Repeat("../", strings.Count(file, "/")) + strings.TrimPrefix(url, "/")
+	if target == "" || strings.HasPrefix(target, "#") {
+		target = "./" + target
+	}
+	return target
+}
+
+// urlKey makes /guides/setup, /guides/setup.html and /guides/setup/ equal.
+func urlKey(url string) string {
+	url = strings.TrimPrefix(strings.TrimSpace(url), "#")
+	url = strings.Trim(url, "/")
+	for _, suffix := range []string{".html", ".md"} {
+		url = strings.TrimSuffix(url, suffix)
+	}
+	if url == "index" {
+		return ""
+	}
+	return strings.TrimSuffix(url, "/index")
+}
+
+func redirectPage(target string) string {
+	escaped := html.EscapeString(target)
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Redirecting…</title>
+<link rel="canonical" href="%[1]s">
+<meta http-equiv="refresh" content="0; url=%[1]s">
+</head>
+<body>
+<p>This page has moved to <a href="%[1]s">%[1]s</a>.</p>
+</body>
+</html>
+`, escaped)
+}
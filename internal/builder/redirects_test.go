@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunAppliesSlugOverrideAndWritesRedirects(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Setup.md", "---\nslug: install\naliases: [/guides/setup]\nredirect_from: /old/setup.html\n---\n# Setup\n")
+	f.doc("guides/faq.md", "---\nslug: questions\n---\n# FAQ\n")
+	f.run()
+
+	for _, path := range []string{"guides/install.html", "guides/questions.html"} {
+		if !f.published(path) {
+			t.Fatalf("expected page at %s", path)
+		}
+	}
+	stubs := map[string]string{
+		"guides/setup/index.html": "../../guides/install.html",
+		"old/setup.html":          "../guides/install.html",
+	}
+	for path, target := range stubs {
+		if data := f.output(path); !strings.Contains(data, `content="0; url=`+target+`"`) {
+			t.Fatalf("unexpected redirect stub %s:\n%s", path, data)
+		}
+	}
+}
+
+func TestCollectRedirectsReportsConflicts(t *testing.T) {
+	cfg := Config{}
+	b := &Builder{cfg: cfg, engine: &htmlEngine{cfg: cfg}}
+	pages := []page{
+		{record: menuRecord{CategoryPath: "guides", Slug: "setup", Source: "/repo/DOC_Setup.md", FrontMatter: frontMatter{Aliases: []string{"/guides/install/"}}}},
+		{record: menuRecord{CategoryPath: "guides", Slug: "install", Source: "/repo/DOC_Install.md", FrontMatter: frontMatter{Aliases: []string{"/start"}}}},
+		{record: menuRecord{CategoryPath: "guides", Slug: "intro", Source: "/repo/DOC_Intro.md", FrontMatter: frontMatter{Aliases: []string{"/start.html"}}}},
+	}
+
+	_, err := b.collectRedirects(pages)
+	if err == nil {
+		t.Fatalf("expected alias conflicts")
+	}
+	for _, expect := range []string{"found 2 alias conflicts", "/guides/install/ of /repo/DOC_Setup.md is the URL of /repo/DOC_Install.md", "/start.html is claimed by /repo/DOC_Install.md and /repo/DOC_Intro.md"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Fatalf("expected %q in error, got %v", expect, err)
+		}
+	}
+
+	pages[0].record.FrontMatter.Aliases = []string{"../escape"}
+	if _, err := b.collectRedirects(pages[:1]); err == nil || !strings.Contains(err.Error(), "invalid alias '../escape'") {
+		t.Fatalf("expected invalid alias error, got %v", err)
+	}
+}
+
+func TestRedirectPath(t *testing.T) {
+	cases := map[string]string{
+		"/guides/setup":      "guides/setup/index.html",
+		"guides/setup/":      "guides/setup/index.html",
+		"/guides/setup.html": "guides/setup.html",
+		"/guides/setup.md":   "guides/setup/index.html",
+	}
+	for alias, want := range cases {
+		got, err := redirectPath(alias)
+		if err != nil || got != want {
+			t.Fatalf("redirectPath(%q) = %q, %v; want %q", alias, got, err, want)
+		}
+	}
+	for _, alias := range []string{"/", "", "/a?b", "/a#b"} {
+		if _, err := redirectPath(alias); err == nil {
+			t.Fatalf("expected redirectPath(%q) to fail", alias)
+		}
+	}
+}
+
+func TestRelativeRedirectTarget(t *testing.T) {
+	cases := []struct{ file, url, want string }{
+		{"setup.html", "/guides/install.html", "guides/install.html"},
+		{"old/guides/setup/index.html", "/guides/install/", "../../../guides/install/"},
+		{"start/index.html", "/", "../"},
+		{"start.html", "/", "./"},
+		{"start.html", "#/guides/install", "./#/guides/install"},
+		{"old/start/index.html", "#/guides/install", "../../#/guides/install"},
+	}
+	for _, c := range cases {
+		if got := relativeRedirectTarget(c.file, c.url); got != c.want {
+			t.Fatalf("relativeRedirectTarget(%q, %q) = %q, want %q", c.file, c.url, got, c.want)
+		}
+	}
+}
@@ -2,67 +2,42 @@ package builder
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 )
 
+// section is a node of the navigation tree. Each category path segment
+// becomes one section; Items holds the pages that live directly in it and
+// Sections the nested categories in display order.
 type section struct {
-	Key         string
-	Title       string
-	Items       []menuRecord
-	subsections map[string]*subsection
-	OrderedSubs []*subsection
+	Key       string
+	Title     string
+	Order     *int
+	Icon      string
+	Collapsed bool
+	Items     []menuRecord
+	Sections  []*section
+	children  map[string]*section
 }
 
-type subsection struct {
-	Key   string
-	Title string
-	Items []menuRecord
-}
-
-func (b *Builder) generateConfig(env environment, records []menuRecord) error {
-	if b.cfg.Verbose {
-		fmt.Println("[4/7] Generating VitePress sidebar configuration")
-	}
-
-	sections := buildSections(records)
-	sidebar := renderSidebar(sections)
-
-	baseData, err := os.ReadFile(env.baseConfig)
-	if err != nil {
-		return fmt.Errorf("failed to read base config %s: %w", env.baseConfig, err)
-	}
-
-	const placeholder = "// SIDEBAR_ITEMS - will be replaced by build script"
-	var output []byte
-
-	baseString := string(baseData)
-	if strings.Contains(baseString, placeholder) {
-		output = []byte(strings.Replace(baseString, placeholder, sidebar, 1))
-	} else if len(sections) > 0 {
-		return fmt.Errorf("placeholder '%s' not found in %s", placeholder, env.baseConfig)
-	} else {
-		output = baseData
-	}
-
-	tempConfig := filepath.Join(env.tempDir, ".vitepress", "config.js")
-	if err := os.MkdirAll(filepath.Dir(tempConfig), 0o755); err != nil {
-		return fmt.Errorf("failed to create temp config directory: %w", err)
-	}
-	if err := os.WriteFile(tempConfig, output, 0o644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", tempConfig, err)
-	}
-	if err := os.WriteFile(env.outputConfig, output, 0o644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", env.outputConfig, err)
+// buildSections groups records into the navigation tree. categories supplies
+// titles, ordering and display options keyed by category path.
+func buildSections(records []menuRecord, categories map[string]CategoryConfig) []*section {
+	root := &section{children: map[string]*section{}}
+	child := func(node *section, key, title string) *section {
+		sub := node.child(key, title)
+		if meta, ok := categories[key]; ok {
+			if meta.Title != "" {
+				sub.Title = meta.Title
+			}
+			sub.Order = meta.Order
+			sub.Icon = meta.Icon
+			if meta.Collapsed != nil {
+				sub.Collapsed = *meta.Collapsed
+			}
+		}
+		return sub
 	}
-
-	return nil
-}
-
-func buildSections(records []menuRecord) []*section {
-	sectionsMap := map[string]*section{}
 	for _, rec := range records {
 		category := normalizeCategoryPath(rec.CategoryPath)
 		parts := []string{}
@@ -70,72 +45,56 @@ func buildSections(records []menuRecord) []*section {
 			parts = strings.Split(category, "/")
 		}
 
-		rootKey := ""
-		if len(parts) > 0 {
-			rootKey = parts[0]
-		}
-
-		sec, exists := sectionsMap[rootKey]
-		if !exists {
-			title := "General"
-			if rootKey != "" {
-				title = formatTitle(rootKey)
-			}
-			sec = &section{
-				Key:         rootKey,
-				Title:       title,
-				Items:       []menuRecord{},
-				subsections: map[string]*subsection{},
-			}
-			sectionsMap[rootKey] = sec
-		}
-
-		if len(parts) <= 1 {
-			sec.Items = append(sec.Items, rec)
+		if len(parts) == 0 {
+			general := child(root, "", "General")
+			general.Items = append(general.Items, rec)
 			continue
 		}
 
-		subKey := strings.Join([]string{rootKey, parts[1]}, "/")
-		sub, exists := sec.subsections[subKey]
-		if !exists {
-			sub = &subsection{
-				Key:   subKey,
-				Title: formatTitle(parts[1]),
-				Items: []menuRecord{},
-			}
-			sec.subsections[subKey] = sub
+		node := root
+		for i, part := range parts {
+			node = child(node, strings.Join(parts[:i+1], "/"), formatTitle(part))
 		}
-		sub.Items = append(sub.Items, rec)
+		node.Items = append(node.Items, rec)
 	}
 
-	keys := make([]string, 0, len(sectionsMap))
-	for key := range sectionsMap {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
+	root.order()
+	return root.Sections
+}
 
-	ordered := make([]*section, 0, len(keys))
-	for _, key := range keys {
-		sec := sectionsMap[key]
-		sortMenuRecords(sec.Items)
-		subKeys := make([]string, 0, len(sec.subsections))
-		for subKey := range sec.subsections {
-			subKeys = append(subKeys, subKey)
-		}
-		sort.Strings(subKeys)
-		orderedSubs := make([]*subsection, 0, len(subKeys))
-		for _, subKey := range subKeys {
-			sub := sec.subsections[subKey]
-			sortMenuRecords(sub.Items)
-			orderedSubs = append(orderedSubs, sub)
+func (s *section) child(key, title string) *section {
+	sub, exists := s.children[key]
+	if !exists {
+		sub = &section{
+			Key:       key,
+			Title:     title,
+			Collapsed: true,
+			Items:     []menuRecord{},
+			children:  map[string]*section{},
 		}
-		sec.OrderedSubs = orderedSubs
-		ordered = append(ordered, sec)
+		s.children[key] = sub
 	}
+	return sub
+}
 
-	return ordered
+func (s *section) order() {
+	sortMenuRecords(s.Items)
+	s.Sections = make([]*section, 0, len(s.children))
+	for _, sub := range s.children {
+		sub.order()
+		s.Sections = append(s.Sections, sub)
+	}
+	sort.Slice(s.Sections, func(i, j int) bool {
+		a, b := s.Sections[i], s.Sections[j]
+		if less, decided := compareOrder(a.Order, b.Order); decided {
+			return less
+		}
+		return a.Key < b.Key
+	})
 }
 
+// sortMenuRecords puts index pages first, then pages with an explicit order and
+// finally the remaining pages alphabetically by title.
 func sortMenuRecords(items []menuRecord) {
 	sort.SliceStable(items, func(i, j int) bool {
 		if items[i].Slug == "index" && items[j].Slug != "index" {
@@ -144,10 +103,27 @@ func sortMenuRecords(items []menuRecord) {
 		if items[i].Slug != "index" && items[j].Slug == "index" {
 			return false
 		}
+		if less, decided := compareOrder(items[i].FrontMatter.Order, items[j].FrontMatter.Order); decided {
+			return less
+		}
 		return strings.ToLower(items[i].Title) < strings.ToLower(items[j].Title)
 	})
 }
 
+// compareOrder ranks explicitly ordered entries before unordered ones. decided
+// is false when both positions are equal or unset.
+func compareOrder(a, b *int) (less, decided bool) {
+	switch {
+	case a != nil && b != nil:
+		return *a < *b, *a != *b
+	case a != nil:
+		return true, true
+	case b != nil:
+		return false, true
+	}
+	return false, false
+}
+
 func renderSidebar(sections []*section) string {
 	if len(sections) == 0 {
 		return ""
@@ -155,31 +131,29 @@ func renderSidebar(sections []*section) string {
 
 	var lines []string
 	for _, sec := range sections {
-		lines = append(lines, "      {")
-		lines = append(lines, fmt.Sprintf("        text: '%s',", escapeQuotes(sec.Title)))
-		lines = append(lines, "        collapsed: true,")
-		lines = append(lines, "        items: [")
-		for _, item := range sec.Items {
-			lines = append(lines, renderSidebarItem(item, 2))
-		}
-		for _, sub := range sec.OrderedSubs {
-			lines = append(lines, "          {")
-			lines = append(lines, fmt.Sprintf("            text: '%s',", escapeQuotes(sub.Title)))
-			lines = append(lines, "            collapsed: true,")
-			lines = append(lines, "            items: [")
-			for _, item := range sub.Items {
-				lines = append(lines, renderSidebarItem(item, 3))
-			}
-			lines = append(lines, "            ]")
-			lines = append(lines, "          },")
-		}
-		lines = append(lines, "        ]")
-		lines = append(lines, "      },")
+		lines = appendSidebarSection(lines, sec, 0)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+func appendSidebarSection(lines []string, sec *section, depth int) []string {
+	indent := strings.Repeat("  ", 3+2*depth)
+	lines = append(lines, indent+"{")
+	lines = append(lines, fmt.Sprintf("%s  text: '%s',", indent, escapeQuotes(sec.Title)))
+	lines = append(lines, fmt.Sprintf("%s  collapsed: %t,", indent, sec.Collapsed))
+	lines = append(lines, indent+"  items: [")
+	for _, item := range sec.Items {
+		lines = append(lines, renderSidebarItem(item, 5+2*depth))
+	}
+	for _, sub := range sec.Sections {
+		lines = appendSidebarSection(lines, sub, depth+1)
+	}
+	lines = append(lines, indent+"  ]")
+	lines = append(lines, indent+"},")
+	return lines
+}
+
 func renderSidebarItem(item menuRecord, depth int) string {
 	indent := strings.Repeat("  ", depth)
 	linkParts := []string{item.CategoryPath, item.Slug}
@@ -13,7 +13,7 @@ func TestBuildSectionsCreatesHierarchies(t *testing.T) {
 		{CategoryPath: "guides/advanced", Slug: "deep-dive", Title: "Deep Dive"},
 	}
 
-	sections := buildSections(records)
+	sections := buildSections(records, nil)
 	if len(sections) != 2 {
 		t.Fatalf("expected 2 sections, got %d", len(sections))
 	}
@@ -33,10 +33,10 @@ func TestBuildSectionsCreatesHierarchies(t *testing.T) {
 	if len(guides.Items) != 1 || guides.Items[0].Slug != "index" {
 		t.Fatalf("expected guides root item to be index, got %+v", guides.Items)
 	}
-	if len(guides.OrderedSubs) != 1 {
-		t.Fatalf("expected one subsection under guides, got %d", len(guides.OrderedSubs))
+	if len(guides.Sections) != 1 {
+		t.Fatalf("expected one subsection under guides, got %d", len(guides.Sections))
 	}
-	sub := guides.OrderedSubs[0]
+	sub := guides.Sections[0]
 	if sub.Title != "Advanced" {
 		t.Fatalf("expected subsection title 'Advanced', got %q", sub.Title)
 	}
@@ -54,7 +54,7 @@ func TestRenderSidebarContainsExpectedEntries(t *testing.T) {
 		{CategoryPath: "platform/mobile", Slug: "index", Title: "Mobile"},
 	}
 
-	sections := buildSections(records)
+	sections := buildSections(records, nil)
 	sidebar := renderSidebar(sections)
 
 	checks := []string{
@@ -69,3 +69,122 @@ func TestRenderSidebarContainsExpectedEntries(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildSectionsSupportsArbitraryDepth(t *testing.T) {
+	records := []menuRecord{
+		{CategoryPath: "architecture/overview", Slug: "system-context", Title: "System Context"},
+		{CategoryPath: "architecture/components/core", Slug: "core-services", Title: "Core Services"},
+		{CategoryPath: "architecture/components/extensions", Slug: "extension-points", Title: "Extension Points"},
+		{CategoryPath: "platform/mobile/features/sync", Slug: "offline-mode", Title: "Offline Mode"},
+	}
+
+	sections := buildSections(records, nil)
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 top-level sections, got %d", len(sections))
+	}
+
+	architecture := sections[0]
+	if len(architecture.Items) != 0 || len(architecture.Sections) != 2 {
+		t.Fatalf("expected architecture to hold two subsections only, got %+v", architecture)
+	}
+	components := architecture.Sections[0]
+	if components.Key != "architecture/components" || len(components.Items) != 0 {
+		t.Fatalf("expected components subsection without direct items, got %+v", components)
+	}
+	if len(components.Sections) != 2 {
+		t.Fatalf("expected core and extensions under components, got %d", len(components.Sections))
+	}
+	core := components.Sections[0]
+	if core.Title != "Core" || len(core.Items) != 1 || core.Items[0].Slug != "core-services" {
+		t.Fatalf("expected core-services nested under components/core, got %+v", core)
+	}
+
+	node := sections[1]
+	for _, key := range []string{"platform/mobile", "platform/mobile/features", "platform/mobile/features/sync"} {
+		if len(node.Sections) != 1 || node.Sections[0].Key != key {
+			t.Fatalf("expected single nested section %q, got %+v", key, node.Sections)
+		}
+		node = node.Sections[0]
+	}
+	if len(node.Items) != 1 || node.Items[0].Slug != "offline-mode" {
+		t.Fatalf("expected offline-mode at depth four, got %+v", node.Items)
+	}
+}
+
+func TestRenderSidebarNestsDeepCategories(t *testing.T) {
+	records := []menuRecord{
+		{CategoryPath: "architecture/components/core", Slug: "core-services", Title: "Core Services"},
+	}
+
+	sidebar := renderSidebar(buildSections(records, nil))
+	lines := strings.Split(sidebar, "\n")
+
+	expected := []string{
+		"      {",
+		"        text: 'Architecture',",
+		"          {",
+		"            text: 'Components',",
+		"              {",
+		"                text: 'Core',",
+		"                  { text: 'Core Services', link: '/architecture/components/core/core-services' },",
+	}
+	idx := 0
+	for _, line := range lines {
+		if idx < len(expected) && line == expected[idx] {
+			idx++
+		}
+	}
+	if idx != len(expected) {
+		t.Fatalf("expected nested sidebar structure, missing %q in:\n%s", expected[idx], sidebar)
+	}
+	if strings.Count(sidebar, "items: [") != 3 {
+		t.Fatalf("expected three nested groups, got:\n%s", sidebar)
+	}
+}
+
+func TestBuildSectionsHonoursOrderAndCategoryMetadata(t *testing.T) {
+	first, second := 1, 2
+	collapsed := false
+	records := []menuRecord{
+		{CategoryPath: "guides", Slug: "advanced", Title: "Advanced"},
+		{CategoryPath: "guides", Slug: "getting-started", Title: "Getting Started", FrontMatter: frontMatter{Order: &first}},
+		{CategoryPath: "guides", Slug: "install", Title: "Install", FrontMatter: frontMatter{Order: &second}},
+		{CategoryPath: "guides", Slug: "index", Title: "Guides"},
+		{CategoryPath: "guides", Slug: "faq", Title: "FAQ"},
+		{CategoryPath: "api", Slug: "intro", Title: "Intro"},
+		{CategoryPath: "reference", Slug: "intro", Title: "Intro"},
+	}
+	categories := map[string]CategoryConfig{
+		"guides":    {Title: "Start Here", Order: &first, Icon: "rocket", Collapsed: &collapsed},
+		"reference": {Order: &second},
+	}
+
+	sections := buildSections(records, categories)
+	var keys []string
+	for _, sec := range sections {
+		keys = append(keys, sec.Key)
+	}
+	if strings.Join(keys, ",") != "guides,reference,api" {
+		t.Fatalf("unexpected section order %v", keys)
+	}
+
+	guides := sections[0]
+	if guides.Title != "Start Here" || guides.Icon != "rocket" || guides.Collapsed {
+		t.Fatalf("expected category metadata to apply, got %+v", guides)
+	}
+	if !sections[2].Collapsed {
+		t.Fatalf("expected categories without metadata to stay collapsed")
+	}
+
+	var slugs []string
+	for _, item := range guides.Items {
+		slugs = append(slugs, item.Slug)
+	}
+	if strings.Join(slugs, ",") != "index,getting-started,install,advanced,faq" {
+		t.Fatalf("unexpected item order %v", slugs)
+	}
+
+	if sidebar := renderSidebar(sections); !strings.Contains(sidebar, "text: 'Start Here',\n        collapsed: false,") {
+		t.Fatalf("expected collapsed state in sidebar:\n%s", sidebar)
+	}
+}
@@ -0,0 +1,260 @@
+package builder
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	snippetPattern     = regexp.MustCompile(`^<!--\s*@snippet:\s*(.*?)\s*-->$`)
+	snippetLinesRange  = regexp.MustCompile(`^L(\d+)(?:-L?(\d+))?$`)
+	regionStartPattern = regexp.MustCompile(`#region\s+([\w.-]+)`)
+	regionEndPattern   = regexp.MustCompile(`#endregion\b`)
+)
+
+// snippetLanguages maps file extensions to the info string of the fenced code
+// block a snippet is wrapped in. Other extensions are used without the dot.
+var snippetLanguages = map[string]string{
+	".go":   "go",
+	".js":   "js",
+	".mjs":  "js",
+	".ts":   "ts",
+	".tsx":  "tsx",
+	".py":   "python",
+	".rb":   "ruby",
+	".rs":   "rust",
+	".sh":   "bash",
+	".bash": "bash",
+	".yml":  "yaml",
+	".yaml": "yaml",
+	".md":   "markdown",
+	".txt":  "text",
+}
+
+// loadSnippet returns the fenced code block for the snippet directive on line
+// of source. spec is a path relative to source with an optional selector:
+// #region=name, #L10-L20 or, for Go files, #symbol=Name or #symbol=Type.Method.
+func loadSnippet(source string, line int, spec string) ([]byte, error) {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%s:%d: snippet %s: %s", source, line, spec, fmt.Sprintf(format, args...))
+	}
+
+	target, selector, _ := strings.Cut(strings.Trim(spec, `"'`), "#")
+	if target == "" {
+		return nil, fmt.Errorf("%s:%d: snippet names no file", source, line)
+	}
+	path := filepath.Clean(filepath.Join(filepath.Dir(source), filepath.FromSlash(target)))
+
+	//nolint:gosec // file path is validated and safe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fail("%v", err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var code string
+	switch {
+	case selector == "":
+		code = content
+	case strings.HasPrefix(selector, "region="):
+		if code, err = snippetRegion(content, strings.TrimPrefix(selector, "region=")); err != nil {
+			return nil, fail("%v", err)
+		}
+	case strings.HasPrefix(selector, "symbol="):
+		if filepath.Ext(path) != ".go" {
+			return nil, fail("symbols can only be selected in Go files")
+		}
+		if code, err = snippetSymbol(path, data, strings.TrimPrefix(selector, "symbol=")); err != nil {
+			return nil, fail("%v", err)
+		}
+	case snippetLinesRange.MatchString(selector):
+		if code, err = snippetLines(content, selector); err != nil {
+			return nil, fail("%v", err)
+		}
+	default:
+		return nil, fail("unknown selector '%s', expected region=name, L10-L20 or symbol=Name", selector)
+	}
+
+	return []byte(fencedBlock(snippetLanguage(path), dedent(code))), nil
+}
+
+// snippetRegion returns the lines between the #region name and #endregion
+// markers of content. Marker lines of nested regions are left out.
+func snippetRegion(content, name string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		match := regionStartPattern.FindStringSubmatch(line)
+		if match == nil || match[1] != name {
+			continue
+		}
+
+		depth := 0
+		var region []string
+		for _, inner := range lines[i+1:] {
+			switch {
+			case regionStartPattern.MatchString(inner):
+				depth++
+				continue
+			case regionEndPattern.MatchString(inner):
+				if depth == 0 {
+					return strings.Join(region, "\n"), nil
+				}
+				depth--
+				continue
+			}
+			region = append(region, inner)
+		}
+		return "", fmt.Errorf("region '%s' is not closed with #endregion", name)
+	}
+	return "", fmt.Errorf("region '%s' not found", name)
+}
+
+// snippetLines returns the 1-based inclusive line range selected by L10-L20
+// or a single line selected by L10.
+func snippetLines(content, selector string) (string, error) {
+	match := snippetLinesRange.FindStringSubmatch(selector)
+	start, _ := strconv.Atoi(match[1])
+	end := start
+	if match[2] != "" {
+		end, _ = strconv.Atoi(match[2])
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if start < 1 || end < start || end > len(lines) {
+		return "", fmt.Errorf("lines %d-%d are outside the file, which has %d lines", start, end, len(lines))
+	}
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
+// snippetSymbol returns the declaration of a top-level Go function, type,
+// constant or variable, or of a method named Type.Method, with its doc comment.
+func snippetSymbol(path string, data []byte, name string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse: %w", err)
+	}
+
+	receiver, method, isMethod := strings.Cut(name, ".")
+	extract := func(doc *ast.CommentGroup, node ast.Node) string {
+		start := node.Pos()
+		if doc != nil {
+			start = doc.Pos()
+		}
+		from := fset.Position(start).Offset
+		// Keep the indentation of the first line so dedent sees every line alike.
+		for from > 0 && (data[from-1] == ' ' || data[from-1] == '\t') {
+			from--
+		}
+		return string(data[from:fset.Position(node.End()).Offset])
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if isMethod {
+				if d.Recv != nil && d.Name.Name == method && receiverName(d.Recv) == receiver {
+					return extract(d.Doc, d), nil
+				}
+			} else if d.Recv == nil && d.Name.Name == name {
+				return extract(d.Doc, d), nil
+			}
+		case *ast.GenDecl:
+			if isMethod {
+				continue
+			}
+			for _, spec := range d.Specs {
+				var names []*ast.Ident
+				var doc *ast.CommentGroup
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names, doc = []*ast.Ident{s.Name}, s.Doc
+				case *ast.ValueSpec:
+					names, doc = s.Names, s.Doc
+				}
+				for _, ident := range names {
+					if ident.Name != name {
+						continue
+					}
+					if !d.Lparen.IsValid() {
+						return extract(d.Doc, d), nil
+					}
+					return extract(doc, spec), nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("symbol '%s' not found", name)
+}
+
+// receiverName returns the type name of a method receiver, without pointer
+// and type parameters.
+func receiverName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func snippetLanguage(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if language, ok := snippetLanguages[ext]; ok {
+		return language
+	}
+	return strings.TrimPrefix(ext, ".")
+}
+
+// dedent removes the indentation shared by every non-blank line of code, along
+// with leading and trailing blank lines.
+func dedent(code string) string {
+	lines := strings.Split(strings.Trim(code, "\n"), "\n")
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fencedBlock wraps code in a fence longer than any backtick run inside it.
+func fencedBlock(language, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + language + "\n" + code + "\n" + fence + "\n"
+}
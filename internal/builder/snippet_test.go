@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const snippetGoSource = `package client
+
+// Client calls the API.
+type Client struct {
+	Retries int
+}
+
+const (
+	// DefaultRetries is used when Retries is zero.
+	DefaultRetries = 3
+	maxBackoff     = 30
+)
+
+// Do sends the request.
+func (c *Client) Do() error {
+	// #region retry
+	for i := 0; i < c.Retries; i++ {
+		// #region inner
+		wait(i)
+		// #endregion
+	}
+	// #endregion
+	return nil
+}
+
+func wait(attempt int) {}
+`
+
+func TestLoadSnippetSelectors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "client.go"), snippetGoSource)
+	writeTestFile(t, filepath.Join(root, "config.yaml"), "a: 1\nb: 2\nc: 3\n")
+	source := filepath.Join(root, "docs", "DOC_Client.md")
+
+	cases := map[string]string{
+		"../client.go#region=retry":          "```go\nfor i := 0; i < c.Retries; i++ {\n\twait(i)\n}\n```\n",
+		"../client.go#symbol=Client":         "```go\n// Client calls the API.\ntype Client struct {\n\tRetries int\n}\n```\n",
+		"../client.go#symbol=DefaultRetries": "```go\n// DefaultRetries is used when Retries is zero.\nDefaultRetries = 3\n```\n",
+		"../client.go#symbol=wait":           "```go\nfunc wait(attempt int) {}\n```\n",
+		"../config.yaml#L2-L3":               "```yaml\nb: 2\nc: 3\n```\n",
+		"../config.yaml#L1":                  "```yaml\na: 1\n```\n",
+		"../config.yaml":                     "```yaml\na: 1\nb: 2\nc: 3\n```\n",
+		"../client.go#symbol=Client.Do":      "```go\n// Do sends the request.\nfunc (c *Client) Do() error {\n",
+	}
+	for spec, want := range cases {
+		got, err := loadSnippet(source, 4, spec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", spec, err)
+		}
+		if !strings.HasPrefix(string(got), want) {
+			t.Fatalf("%s: got\n%s\nwant prefix\n%s", spec, got, want)
+		}
+	}
+}
+
+func TestLoadSnippetErrors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "client.go"), snippetGoSource)
+	writeTestFile(t, filepath.Join(root, "open.js"), "// #region setup\nconst a = 1\n")
+	source := filepath.Join(root, "DOC_Client.md")
+
+	cases := map[string]string{
+		"missing.go#region=retry":  "no such file",
+		"client.go#region=backoff": "region 'backoff' not found",
+		"open.js#region=setup":     "region 'setup' is not closed with #endregion",
+		"client.go#L20-L90":        "lines 20-90 are outside the file",
+		"client.go#symbol=Missing": "symbol 'Missing' not found",
+		"open.js#symbol=setup":     "symbols can only be selected in Go files",
+		"client.go#function=Do":    "unknown selector 'function=Do'",
+	}
+	for spec, want := range cases {
+		_, err := loadSnippet(source, 7, spec)
+		prefix := source + ":7: snippet " + spec + ": "
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: error = %v, want %q after %q", spec, err, want, prefix)
+		}
+	}
+}
+
+func TestExpandIncludesRendersSnippets(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "shared", "example.md"), "Example:\n<!-- @snippet: run.sh#L2 -->\n")
+	writeTestFile(t, filepath.Join(root, "shared", "run.sh"), "#!/bin/sh\necho \"```\"\n")
+
+	pages := []page{{record: menuRecord{Source: filepath.Join(root, "DOC_Run.md")}, data: []byte("# Run\n<!-- @include: shared/example.md -->\n")}}
+	if err := (&Builder{}).expandIncludes(pages); err != nil {
+		t.Fatalf("expandIncludes returned error: %v", err)
+	}
+	want := "# Run\nExample:\n````bash\necho \"```\"\n````\n"
+	if got := string(pages[0].data); got != want {
+		t.Fatalf("unexpected expansion:\n%s\nwant:\n%s", got, want)
+	}
+}
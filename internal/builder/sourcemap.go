@@ -0,0 +1,157 @@
+package builder
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// buildErrorContext is the number of trailing output lines attached to the
+// error of a failed build tool.
+const buildErrorContext = 20
+
+// sourceMap records where every page of the workspace came from. Keys are
+// slash separated paths relative to the content directory, values are the
+// absolute paths of the original markdown files.
+type sourceMap map[string]string
+
+func (m sourceMap) record(env environment, target, source string) {
+	rel, err := filepath.Rel(env.contentDir, target)
+	if err != nil {
+		return
+	}
+	m[filepath.ToSlash(rel)] = source
+}
+
+// rename moves the entry of a workspace file that an engine renamed.
+func (m sourceMap) rename(env environment, from, to string) {
+	rel, err := filepath.Rel(env.contentDir, from)
+	if err != nil {
+		return
+	}
+	if source, ok := m[filepath.ToSlash(rel)]; ok {
+		delete(m, filepath.ToSlash(rel))
+		m.record(env, to, source)
+	}
+}
+
+// sourceRewriter replaces workspace paths in tool output with the path of the
+// original source file.
+type sourceRewriter struct {
+	pattern *regexp.Regexp
+	sources sourceMap
+}
+
+func newSourceRewriter(env environment) *sourceRewriter {
+	if len(env.sources) == 0 {
+		return &sourceRewriter{}
+	}
+
+	keys := make([]string, 0, len(env.sources))
+	for key := range env.sources {
+		keys = append(keys, regexp.QuoteMeta(key))
+	}
+	// Longer keys first so nested paths win over their suffixes.
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	prefixes := []string{filepath.ToSlash(env.contentDir) + "/"}
+	workspaceRel := ""
+	if rel, err := filepath.Rel(env.tempDir, env.contentDir); err == nil && rel != "." {
+		workspaceRel = filepath.ToSlash(rel) + "/"
+	}
+	prefixes = append(prefixes, filepath.Base(env.tempDir)+"/"+workspaceRel)
+	if workspaceRel != "" {
+		prefixes = append(prefixes, workspaceRel)
+	}
+	for i, prefix := range prefixes {
+		prefixes[i] = regexp.QuoteMeta(prefix)
+	}
+
+	pattern := `(^|[\s"'(\[<:=,])(` + strings.Join(prefixes, "|") + `)?(` + strings.Join(keys, "|") + `)\b`
+	return &sourceRewriter{pattern: regexp.MustCompile(pattern), sources: env.sources}
+}
+
+func (r *sourceRewriter) rewrite(line string) string {
+	if r.pattern == nil {
+		return line
+	}
+	return r.pattern.ReplaceAllStringFunc(line, func(match string) string {
+		groups := r.pattern.FindStringSubmatch(match)
+		return groups[1] + displayPath(r.sources[groups[3]])
+	})
+}
+
+// displayPath shortens path relative to the working directory when it lies
+// below it.
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// mappedOutput is an io.Writer that rewrites complete lines through the
+// sourceRewriter before passing them on, and keeps the most recent lines so
+// they can be attached to an error.
+type mappedOutput struct {
+	mu       *sync.Mutex
+	out      io.Writer
+	rewriter *sourceRewriter
+	pending  []byte
+	tail     *[]string
+}
+
+func (w *mappedOutput) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		if err := w.emit(string(w.pending[:idx])); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[idx+1:]
+	}
+	return len(p), nil
+}
+
+func (w *mappedOutput) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := string(w.pending)
+	w.pending = nil
+	return w.emit(line)
+}
+
+func (w *mappedOutput) emit(line string) error {
+	line = w.rewriter.rewrite(strings.TrimSuffix(line, "\r"))
+	*w.tail = append(*w.tail, line)
+	if len(*w.tail) > buildErrorContext {
+		*w.tail = (*w.tail)[len(*w.tail)-buildErrorContext:]
+	}
+	_, err := io.WriteString(w.out, line+"\n")
+	return err
+}
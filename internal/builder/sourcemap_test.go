@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSourceRewriterMapsWorkspacePaths(t *testing.T) {
+	root := t.TempDir()
+	tempDir := filepath.Join(root, ".doc", ".temp")
+	env := environment{
+		tempDir:    tempDir,
+		contentDir: filepath.Join(tempDir, "docs"),
+		sources:    sourceMap{},
+	}
+	source := filepath.Join(root, "pkg", "api", "docs", "Api_guide.md")
+	env.sources.record(env, filepath.Join(env.contentDir, "api", "guide.md"), source)
+
+	// Hugo renames section index pages after they were recorded.
+	sectionSource := filepath.Join(root, "docs", "api", "index.md")
+	sectionIndex := filepath.Join(env.contentDir, "api", "index.md")
+	writeTestFile(t, sectionIndex, "# API\n")
+	env.sources.record(env, sectionIndex, sectionSource)
+	if err := promoteHugoIndex(env, "api", nil); err != nil {
+		t.Fatalf("promoteHugoIndex returned error: %v", err)
+	}
+
+	rewriter := newSourceRewriter(env)
+	want := displayPath(source)
+
+	cases := map[string]string{
+		"Error in " + filepath.Join(env.contentDir, "api", "guide.md") + ":12:3": "Error in " + want + ":12:3",
+		"[vite] docs/api/guide.md: unexpected token":                             "[vite] " + want + ": unexpected token",
+		"at .temp/docs/api/guide.md (line 4)":                                    "at " + want + " (line 4)",
+		"api/guide.md:1":                                                         want + ":1",
+		"see myapi/guide.md":                                                     "see myapi/guide.md",
+		"docs/api/_index.md:2: unclosed shortcode":                               displayPath(sectionSource) + ":2: unclosed shortcode",
+	}
+	for input, expected := range cases {
+		if got := rewriter.rewrite(input); got != expected {
+			t.Fatalf("rewrite(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestRunToolReportsMappedOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executables require a POSIX shell")
+	}
+
+	root := t.TempDir()
+	tempDir := filepath.Join(root, ".temp")
+	env := environment{tempDir: tempDir, contentDir: tempDir, sources: sourceMap{}}
+	source := filepath.Join(root, "src", "Guide_intro.md")
+	env.sources.record(env, filepath.Join(tempDir, "guide", "intro.md"), source)
+	if err := os.MkdirAll(tempDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	tool := filepath.Join(root, "tool")
+	writeTestFile(t, tool, "#!/bin/sh\necho \"build error in $PWD/guide/intro.md:3\" >&2\nexit 1\n")
+	if err := os.Chmod(tool, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	err := runTool(context.Background(), env, tool, "build")
+	if err == nil {
+		t.Fatalf("expected runTool to fail")
+	}
+	if !strings.Contains(err.Error(), "build error in "+displayPath(source)+":3") {
+		t.Fatalf("expected mapped source path in error, got %v", err)
+	}
+}
@@ -8,43 +8,12 @@ import (
 	"unicode"
 )
 
-func parseFrontMatter(content []byte) map[string]string {
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	fm := map[string]string{}
-	if !scanner.Scan() {
-		return fm
-	}
-	first := strings.TrimSpace(scanner.Text())
-	if first != "---" {
-		return fm
-	}
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "---" {
-			break
-		}
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key := strings.ToLower(strings.TrimSpace(parts[0]))
-		value := strings.TrimSpace(parts[1])
-		value = strings.Trim(value, "\"'")
-		fm[key] = value
-	}
-	return fm
-}
-
 func deriveTitle(content []byte, frontMatterTitle string, slug string, prefix string) string {
 	if frontMatterTitle != "" {
 		return frontMatterTitle
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner := bufio.NewScanner(bytes.NewReader(stripFrontMatter(content)))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if strings.HasPrefix(line, "# ") {
@@ -85,10 +54,6 @@ func normalizeCategoryPath(path string) string {
 	return path
 }
 
-func menuKey(category, slug string) string {
-	return category + "|" + slug
-}
-
 func toTitleCase(value string) string {
 	words := strings.Fields(value)
 	if len(words) == 0 {
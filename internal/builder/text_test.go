@@ -2,25 +2,6 @@ package builder
 
 import "testing"
 
-func TestParseFrontMatter(t *testing.T) {
-	content := []byte("---\nTitle: Getting Started\nCategory: guides/intro\n---\n# Body\n")
-	fm := parseFrontMatter(content)
-
-	if got := fm["title"]; got != "Getting Started" {
-		t.Fatalf("expected title to be 'Getting Started', got %q", got)
-	}
-	if got := fm["category"]; got != "guides/intro" {
-		t.Fatalf("expected category to be 'guides/intro', got %q", got)
-	}
-}
-
-func TestParseFrontMatterWithoutBlock(t *testing.T) {
-	content := []byte("# Heading only\n")
-	if fm := parseFrontMatter(content); len(fm) != 0 {
-		t.Fatalf("expected empty map, got %v", fm)
-	}
-}
-
 func TestDeriveTitleOrdering(t *testing.T) {
 	content := []byte("---\ntitle: Front Matter Title\n---\n# Markdown Heading\n")
 	title := deriveTitle(content, "Front Matter Title", "doc-title", "DOC_")
@@ -55,9 +36,3 @@ func TestBuildSlugAndNormalizeCategory(t *testing.T) {
 		t.Fatalf("expected '.' to normalize to empty string, got %q", path)
 	}
 }
-
-func TestMenuKeyIncludesCategoryAndSlug(t *testing.T) {
-	if key := menuKey("guides", "intro"); key != "guides|intro" {
-		t.Fatalf("unexpected key %q", key)
-	}
-}
@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+)
+
+// navigationOnlyEngine is implemented by engines that only publish the pages
+// listed in their navigation, such as mdBook with its SUMMARY.md.
+type navigationOnlyEngine interface {
+	publishesNavigationOnly()
+}
+
+// skippedPage is a source left out of the published site or its navigation.
+type skippedPage struct {
+	Source string
+	Reason string
+}
+
+// excludeDrafts drops pages marked draft: true. With IncludeDrafts they are
+// kept and their draft flag is cleared, so engines that honour it themselves,
+// such as Hugo and Docusaurus, publish them as well.
+func (b *Builder) excludeDrafts(pages []page) ([]page, []skippedPage) {
+	kept := make([]page, 0, len(pages))
+	var skipped []skippedPage
+	for _, p := range pages {
+		if !p.record.FrontMatter.Draft {
+			kept = append(kept, p)
+			continue
+		}
+		if !b.cfg.IncludeDrafts {
+			skipped = append(skipped, skippedPage{Source: p.record.Source, Reason: "draft, publish with --include-drafts"})
+			continue
+		}
+		p.data = setFrontMatterFields(p.data, []frontMatterField{{"draft", "false"}})
+		kept = append(kept, p)
+		if b.cfg.Verbose {
+			fmt.Printf("  including draft %s\n", p.record.Source)
+		}
+	}
+	return kept, skipped
+}
+
+// hiddenPages lists the published pages left out of the navigation.
+func hiddenPages(pages []page) []skippedPage {
+	var hidden []skippedPage
+	for _, p := range pages {
+		if p.record.FrontMatter.Hidden && !p.home {
+			hidden = append(hidden, skippedPage{Source: p.record.Source, Reason: "hidden from the sidebar"})
+		}
+	}
+	return hidden
+}
+
+// revealHiddenPages keeps hidden pages in the navigation of engines that only
+// publish what their navigation lists, so they are not silently dropped, and
+// warns about each one.
+func (b *Builder) revealHiddenPages(pages []page) {
+	if _, ok := b.engine.(navigationOnlyEngine); !ok {
+		return
+	}
+	for i := range pages {
+		p := &pages[i]
+		if !p.record.FrontMatter.Hidden || p.home {
+			continue
+		}
+		p.record.FrontMatter.Hidden = false
+		fmt.Fprintf(os.Stderr, "warning: %s only publishes pages listed in its navigation, listing hidden page %s in the sidebar\n", b.engine.Name(), displayPath(p.record.Source))
+	}
+}
@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExcludeDrafts(t *testing.T) {
+	pages := []page{
+		{record: menuRecord{Slug: "ready", Source: "/repo/DOC_Ready.md"}, data: []byte("# Ready\n")},
+		{record: menuRecord{Slug: "wip", Source: "/repo/DOC_Wip.md", FrontMatter: frontMatter{Draft: true}}, data: []byte("---\ndraft: true\n---\n# WIP\n")},
+	}
+
+	b := &Builder{}
+	kept, skipped := b.excludeDrafts(pages)
+	if len(kept) != 1 || kept[0].record.Slug != "ready" {
+		t.Fatalf("expected only the ready page, got %+v", kept)
+	}
+	if len(skipped) != 1 || skipped[0].Source != "/repo/DOC_Wip.md" || !strings.Contains(skipped[0].Reason, "draft") {
+		t.Fatalf("unexpected skipped pages %+v", skipped)
+	}
+
+	b.cfg.IncludeDrafts = true
+	kept, skipped = b.excludeDrafts(pages)
+	if len(kept) != 2 || len(skipped) != 0 {
+		t.Fatalf("expected drafts to be kept, got %+v %+v", kept, skipped)
+	}
+	if string(kept[1].data) != "---\ndraft: false\n---\n# WIP\n" {
+		t.Fatalf("expected draft flag to be cleared, got %q", kept[1].data)
+	}
+}
+
+func TestRunSkipsDraftsAndHidesPages(t *testing.T) {
+	f := newRunFixture(t)
+	f.source("DOC_Guide.md", "# Guide\n")
+	f.source("DOC_Wip.md", "---\ndraft: true\n---\n# Work In Progress\n")
+	f.source("DOC_Legacy.md", "---\nsidebar: false\n---\n# Legacy Notes\n")
+	f.run()
+
+	if f.published("guides/wip.html") {
+		t.Fatalf("expected draft to be skipped")
+	}
+	if data := f.output("guides/guide.html"); strings.Contains(data, "Legacy Notes") {
+		t.Fatalf("expected hidden page to be left out of the sidebar:\n%s", data)
+	}
+	if !f.published("guides/legacy.html") {
+		t.Fatalf("expected hidden page to be published")
+	}
+
+	f.cfg.IncludeDrafts = true
+	f.run()
+	if !f.published("guides/wip.html") {
+		t.Fatalf("expected draft with IncludeDrafts")
+	}
+}
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const vitepressPlaceholder = "// SIDEBAR_ITEMS - will be replaced by build script"
+
+func init() {
+	registerEngine("vitepress", func(cfg Config) Engine {
+		return &vitepressEngine{cfg: cfg}
+	})
+}
+
+// vitepressEngine renders the sidebar into .vitepress/base.config.js and
+// builds the site with `npm run docs:build`.
+type vitepressEngine struct {
+	cfg Config
+}
+
+func (e *vitepressEngine) Name() string {
+	return "vitepress"
+}
+
+func (e *vitepressEngine) Prepare(env *environment) error {
+	if err := requireFile(e.baseConfig(*env)); err != nil {
+		return err
+	}
+	if err := requireFile(filepath.Join(env.docDir, "package.json")); err != nil {
+		return err
+	}
+	env.contentDir = env.tempDir
+	env.reservedDirs = append(env.reservedDirs, filepath.Join(env.docDir, ".vitepress"))
+	return nil
+}
+
+func (e *vitepressEngine) RenderNavigation(env environment, sections []*section) error {
+	if e.cfg.Verbose {
+		fmt.Println("[4/7] Generating VitePress sidebar configuration")
+	}
+
+	sidebar := renderSidebar(sections)
+
+	baseConfig := e.baseConfig(env)
+	baseData, err := os.ReadFile(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read base config %s: %w", baseConfig, err)
+	}
+
+	var output []byte
+
+	baseString := string(baseData)
+	if strings.Contains(baseString, vitepressPlaceholder) {
+		output = []byte(strings.Replace(baseString, vitepressPlaceholder, sidebar, 1))
+	} else if len(sections) > 0 {
+		return fmt.Errorf("placeholder '%s' not found in %s", vitepressPlaceholder, baseConfig)
+	} else {
+		output = baseData
+	}
+
+	tempConfig := filepath.Join(env.tempDir, ".vitepress", "config.js")
+	if err := os.MkdirAll(filepath.Dir(tempConfig), 0o755); err != nil {
+		return fmt.Errorf("failed to create temp config directory: %w", err)
+	}
+	if err := os.WriteFile(tempConfig, output, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tempConfig, err)
+	}
+	outputConfig := filepath.Join(env.docDir, ".vitepress", "config.js")
+	if err := os.WriteFile(outputConfig, output, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputConfig, err)
+	}
+
+	return nil
+}
+
+func (e *vitepressEngine) Build(ctx context.Context, env environment) error {
+	pm, err := installDependencies(ctx, e.cfg, env)
+	if err != nil {
+		return err
+	}
+	return runPackageScript(ctx, e.cfg, env, pm, "docs:build")
+}
+
+func (e *vitepressEngine) Output(env environment) (string, string) {
+	return filepath.Join(env.tempDir, ".vitepress", "dist"), filepath.Join(env.docDir, ".vitepress", "dist")
+}
+
+func (e *vitepressEngine) PageURL(rec menuRecord) string {
+	return sitePath(rec, ".html", true)
+}
+
+func (e *vitepressEngine) baseConfig(env environment) string {
+	return filepath.Join(env.docDir, ".vitepress", "base.config.js")
+}